@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/singlaanish56/Interpreter-In-Go/token"
@@ -161,6 +162,16 @@ func (sl *StringLiteral) expressionNode(){}
 func (sl *StringLiteral) TokenLiteral() string {return sl.Token.Identifier}
 func (sl *StringLiteral) String() string {return sl.Token.Identifier}
 
+//`"text ${expression} text"`, the parts are string literals and the embedded expressions in order
+type InterpolatedString struct{
+	Token token.Token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode(){}
+func (is *InterpolatedString) TokenLiteral() string {return is.Token.Identifier}
+func (is *InterpolatedString) String() string {return is.Token.Identifier}
+
 type ArrayLiteral struct{
 	Token token.Token
 	Elements []Expression
@@ -183,9 +194,15 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+type HashLiteralPair struct{
+	Key Expression
+	Value Expression
+}
+
+//pairs are kept in source order
 type HashLiteral struct{
 	Token token.Token
-	Pairs map[Expression]Expression
+	Pairs []HashLiteralPair
 }
 
 func (hl *HashLiteral) expressionNode(){}
@@ -194,8 +211,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	elements:= []string{}
-	for k,v:= range hl.Pairs{
-		elements = append(elements, k.String()+":"+v.String())
+	for _, p:= range hl.Pairs{
+		elements = append(elements, p.Key.String()+":"+p.Value.String())
 	}
 
 	out.WriteString("{")
@@ -229,9 +246,28 @@ func (ie *IfExpression) String() string{
 	return out.String()
 }
 
+//`condition ? consequence : alternative`
+type TernaryExpression struct{
+	Token token.Token
+	Condition Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (te *TernaryExpression) expressionNode(){}
+func (te *TernaryExpression) TokenLiteral() string{return te.Token.Identifier}
+func (te *TernaryExpression) String() string{
+	return "(" + te.Condition.String() + " ? " + te.Consequence.String() + " : " + te.Alternative.String() + ")"
+}
+
+//also what the arrow forms `x => body` and `(x, y) => body` parse into
 type FunctionExpression struct{
 	Token token.Token
+	Name string // empty for anonymous functions
+	BoundName string // the let binding an anonymous function is assigned to, only used in error traces
 	Parameters []*Variable
+	Defaults map[string]Expression // default value per parameter name, evaluated at call time
+	Rest *Variable // collects the remaining arguments into an array, nil if not variadic
 	Body *BlockStatement
 }
 
@@ -240,21 +276,103 @@ func (fe *FunctionExpression) TokenLiteral() string{return fe.Token.Identifier}
 func (fe *FunctionExpression) String() string{
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range fe.Parameters{
-		params = append(params, p.String())
+	out.WriteString("fn")
+	if fe.Name != ""{
+		out.WriteString(" "+fe.Name)
 	}
-
-
-	out.WriteString("if")
 	out.WriteString("(")
-	out.WriteString(strings.Join(params,","))
+	out.WriteString(ParametersString(fe.Parameters, fe.Defaults, fe.Rest))
 	out.WriteString(")")
 	out.WriteString(fe.Body.String())
 
 	return out.String()
 }
 
+//renders a parameter list the way it was written, shared with the function object
+func ParametersString(params []*Variable, defaults map[string]Expression, rest *Variable) string{
+	list := []string{}
+	for _, p := range params{
+		if def, ok := defaults[p.Value]; ok{
+			list = append(list, p.String()+"="+def.String())
+			continue
+		}
+		list = append(list, p.String())
+	}
+
+	if rest != nil{
+		list = append(list, "..."+rest.String())
+	}
+
+	return strings.Join(list, ",")
+}
+
+//`fn name(params){}` declaration, the name is hoisted to the top of the enclosing block
+type FunctionStatement struct{
+	Token token.Token
+	Name *Variable
+	Function *FunctionExpression
+}
+
+func (fs *FunctionStatement) statementNode(){}
+func (fs *FunctionStatement) TokenLiteral() string{return fs.Token.Identifier}
+func (fs *FunctionStatement) String() string{return fs.Function.String()}
+
+//`import "path" as name;` binds the whole module, `import { a, b } from "path";` binds single exports
+type ImportStatement struct{
+	Token token.Token
+	Path string
+	Alias *Variable
+	Names []*Variable
+}
+
+func (is *ImportStatement) statementNode(){}
+func (is *ImportStatement) TokenLiteral() string{return is.Token.Identifier}
+func (is *ImportStatement) String() string{
+	if is.Alias != nil{
+		return fmt.Sprintf("import %q as %s;", is.Path, is.Alias.Value)
+	}
+
+	names := []string{}
+	for _, name := range is.Names{
+		names = append(names, name.Value)
+	}
+
+	return fmt.Sprintf("import { %s } from %q;", strings.Join(names, ", "), is.Path)
+}
+
+//`export let` or `export fn`, marks a top level binding of a module as importable
+type ExportStatement struct{
+	Token token.Token
+	Statement Statement
+}
+
+func (es *ExportStatement) statementNode(){}
+func (es *ExportStatement) TokenLiteral() string{return es.Token.Identifier}
+func (es *ExportStatement) String() string{return "export "+es.Statement.String()}
+
+//the name the exported statement binds
+func (es *ExportStatement) Name() string{
+	switch st := es.Statement.(type){
+	case *LetStatement:
+		return st.Variable.Value
+	case *FunctionStatement:
+		return st.Name.Value
+	default:
+		return ""
+	}
+}
+
+type SpreadExpression struct{
+	Token token.Token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode(){}
+func (se *SpreadExpression) TokenLiteral() string{return se.Token.Identifier}
+func (se *SpreadExpression) String() string{
+	return "..."+se.Value.String()
+}
+
 type CallExpression struct{
 	Token token.Token
 	Function Expression
@@ -321,10 +439,12 @@ func (pe *InfixExpression) String() string{
 	return out.String()
 }
 
+//`left[index]`, `left?[index]` turns the rest of the chain into null when left is null
 type IndexExpression struct{
 	Token token.Token
 	Left Expression
 	Index Expression
+	Optional bool
 }
 
 func (ie *IndexExpression) expressionNode(){}
@@ -334,10 +454,54 @@ func (ie *IndexExpression) String() string{
 
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
-	out.WriteString("[")
+	out.WriteString(ie.Token.Identifier)
 	out.WriteString(ie.Index.String())
 	out.WriteString("]")
 	out.WriteString(")")
 
 	return out.String()
-}
\ No newline at end of file
+}
+
+//`left.name`, or `left?.name` which turns the rest of the chain into null when left is null
+type MemberExpression struct{
+	Token token.Token
+	Left Expression
+	Property *Variable
+	Optional bool
+}
+
+func (me *MemberExpression) expressionNode(){}
+func (me *MemberExpression) TokenLiteral() string{return me.Token.Identifier}
+func (me *MemberExpression) String() string{
+	return "(" + me.Left.String() + me.Token.Identifier + me.Property.String() + ")"
+}
+
+//`left[start:end]`, either bound can be left out
+type SliceExpression struct{
+	Token token.Token
+	Left Expression
+	Start Expression
+	End Expression
+	Optional bool
+}
+
+func (se *SliceExpression) expressionNode(){}
+func (se *SliceExpression) TokenLiteral() string{return se.Token.Identifier}
+func (se *SliceExpression) String() string{
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString(se.Token.Identifier)
+	if se.Start != nil{
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil{
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("]")
+	out.WriteString(")")
+
+	return out.String()
+}
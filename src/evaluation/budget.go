@@ -0,0 +1,110 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+//the cause of the error an evaluation stops with once it runs over one of its limits
+var ErrResourceExhausted = errors.New("resource exhausted")
+
+//caps for a single context, zero leaves that resource unlimited
+type Limits struct{
+	MaxSteps int64
+	MaxAllocated int64 // bytes of strings, arrays and hashes created
+}
+
+//what the context has used so far, it keeps counting across evaluations until ResetUsage
+type Usage struct{
+	Steps int64 // nodes evaluated
+	Allocated int64
+}
+
+func (ctx *Context) Usage() Usage{
+	return ctx.usage
+}
+
+func (ctx *Context) ResetUsage(){
+	ctx.usage = Usage{}
+}
+
+func (ctx *Context) step() *object.Error{
+	ctx.usage.Steps++
+	if ctx.Limits.MaxSteps > 0 && ctx.usage.Steps > ctx.Limits.MaxSteps{
+		return exhausted("step budget of %d exceeded", ctx.Limits.MaxSteps)
+	}
+
+	return nil
+}
+
+//counts a freshly created object against the allocation budget, handing it back or the error if it does not fit
+func (ctx *Context) allocate(obj object.Object) object.Object{
+	ctx.usage.Allocated += allocationSize(obj)
+	if ctx.Limits.MaxAllocated > 0 && ctx.usage.Allocated > ctx.Limits.MaxAllocated{
+		return exhausted("allocation budget of %d bytes exceeded", ctx.Limits.MaxAllocated)
+	}
+
+	return obj
+}
+
+//counts what a builtin built, handing back an argument or one of its elements (first, max, clamp...) creates nothing
+func (ctx *Context) allocateResult(result object.Object, args []object.Object) object.Object{
+	if allocationSize(result) == 0 || reusesArgument(result, args){
+		return result
+	}
+
+	return ctx.allocate(result)
+}
+
+func reusesArgument(result object.Object, args []object.Object) bool{
+	for _, arg := range args{
+		if arg == result{
+			return true
+		}
+		switch arg := arg.(type){
+		case *object.Array:
+			for _, el := range arg.Elements{
+				if el == result{
+					return true
+				}
+			}
+		case *object.Hash:
+			for _, pair := range arg.Pairs(){
+				if pair.Key == result || pair.Value == result{
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+//only checks against the budget, what gets built is still counted by allocate once it is returned
+func (ctx *Context) Reserve(size int64) *object.Error{
+	if ctx.Limits.MaxAllocated > 0 && size > ctx.Limits.MaxAllocated-ctx.usage.Allocated{
+		return exhausted("allocation budget of %d bytes exceeded", ctx.Limits.MaxAllocated)
+	}
+
+	return nil
+}
+
+//a rough size that only counts the object itself, its elements were counted where they were created
+func allocationSize(obj object.Object) int64{
+	switch obj := obj.(type){
+	case *object.String:
+		return int64(len(obj.Value))
+	case *object.Array:
+		return int64(len(obj.Elements)) * 8
+	case *object.Hash:
+		return int64(obj.Len()) * 16
+	default:
+		return 0
+	}
+}
+
+func exhausted(format string, a ...interface{}) *object.Error{
+	return &object.Error{Message: "resource exhausted: " + fmt.Sprintf(format, a...), Cause: ErrResourceExhausted}
+}
@@ -1,30 +1,32 @@
 package evaluation
 
 import (
-	"fmt"
+	"unicode/utf8"
 
 	"github.com/singlaanish56/Interpreter-In-Go/object"
 )
 
 var builtins = map[string]*object.Builtin{
 	"len": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
 			if len(args)!=1{
 				return newError("wrong number of args, expected=1, got=%d", len(args))
 			}
 
 			switch arg := args[0].(type){
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Hash:
+				return &object.Integer{Value: int64(arg.Len())}
 			default:
 				return newError("argument for the len builtin not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"first": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
 			if len(args)!=1{
 				return newError("wrong number of args, expected=1, got=%d", len(args))
 			}
@@ -41,7 +43,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
 			if len(args)!=1{
 				return newError("wrong number of args, expected=1, got=%d", len(args))
 			}
@@ -58,7 +60,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
 			if len(args)!=1{
 				return newError("wrong number of args, expected=1, got=%d", len(args))
 			}
@@ -71,14 +73,14 @@ var builtins = map[string]*object.Builtin{
 					copy(newArr, arg.Elements[1:sz])
 					return &object.Array{Elements: newArr}
 				}
-				return NULL
+				return &object.Array{Elements: []object.Object{}}
 			default:
 				return newError("argument for the rest builtin not supported, got %s", args[0].Type())
 			}
 		},
 	},
 	"push_back": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
 			if len(args)!=2{
 				return newError("wrong number of args, expected=2, got=%d", len(args))
 			}
@@ -86,25 +88,59 @@ var builtins = map[string]*object.Builtin{
 			switch arg := args[0].(type){
 			case *object.Array:
 				sz := len(arg.Elements)
-				if sz > 0{
-					newArr := make([]object.Object, sz+1)
-					copy(newArr, arg.Elements)
-					newArr[sz] =args[1]
-					return &object.Array{Elements: newArr}
-				}
-				return NULL
+				newArr := make([]object.Object, sz+1)
+				copy(newArr, arg.Elements)
+				newArr[sz] =args[1]
+				return &object.Array{Elements: newArr}
 			default:
 				return newError("argument for the push_back builtin not supported, got %s", args[0].Type())
 			}
 		},
 	},
-	"print": &object.Builtin{
-		Fn : func(args ...object.Object) object.Object{
-			for _, arg := range args{
-				fmt.Println(arg.Inspect())
-			}
+}
 
-			return NULL
-		},
-	},
-}
\ No newline at end of file
+//the most elements, or bytes of a string, a single builtin builds in one go, bigger results are an error instead of a crash of the host
+const maxBuiltinResult = 1 << 24
+
+//adds a set of builtins defined in another file to the registry
+func registerBuiltins(set map[string]*object.Builtin){
+	for name, builtin := range set{
+		builtins[name] = builtin
+	}
+}
+
+func stringArg(name string, arg object.Object) (string, *object.Error){
+	str, ok := arg.(*object.String)
+	if !ok{
+		return "", newError("argument for the %s builtin not supported, got %s", name, arg.Type())
+	}
+
+	return str.Value, nil
+}
+
+func integerArg(name string, arg object.Object) (int64, *object.Error){
+	integer, ok := arg.(*object.Integer)
+	if !ok{
+		return 0, newError("argument for the %s builtin not supported, got %s", name, arg.Type())
+	}
+
+	return integer.Value, nil
+}
+
+func hashArg(name string, arg object.Object) (*object.Hash, *object.Error){
+	hash, ok := arg.(*object.Hash)
+	if !ok{
+		return nil, newError("argument for the %s builtin not supported, got %s", name, arg.Type())
+	}
+
+	return hash, nil
+}
+
+func arrayArg(name string, arg object.Object) (*object.Array, *object.Error){
+	arr, ok := arg.(*object.Array)
+	if !ok{
+		return nil, newError("argument for the %s builtin not supported, got %s", name, arg.Type())
+	}
+
+	return arr, nil
+}
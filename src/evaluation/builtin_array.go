@@ -0,0 +1,256 @@
+package evaluation
+
+import (
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(arrayBuiltins)
+}
+
+//like rest and push_back, every array builtin returns a new array and leaves its arguments untouched
+var arrayBuiltins = map[string]*object.Builtin{
+	"pop": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("pop", args[0])
+			if err != nil{
+				return err
+			}
+
+			if len(arr.Elements) == 0{
+				return &object.Array{Elements: []object.Object{}}
+			}
+
+			return copyArray(arr.Elements[:len(arr.Elements)-1])
+		},
+	},
+	"insert": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=3{
+				return newError("wrong number of args, expected=3, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("insert", args[0])
+			if err != nil{
+				return err
+			}
+
+			idx, err := integerArg("insert", args[1])
+			if err != nil{
+				return err
+			}
+
+			//same rules as a slice bound, negative counts from the end and out of range is clamped
+			pos := clampSliceBound(idx, int64(len(arr.Elements)))
+
+			newArr := make([]object.Object, 0, len(arr.Elements)+1)
+			newArr = append(newArr, arr.Elements[:pos]...)
+			newArr = append(newArr, args[2])
+			newArr = append(newArr, arr.Elements[pos:]...)
+			return &object.Array{Elements: newArr}
+		},
+	},
+	"concat": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			size := 0
+			for _, arg := range args{
+				arr, err := arrayArg("concat", arg)
+				if err != nil{
+					return err
+				}
+				size += len(arr.Elements)
+			}
+			if err := rt.Reserve(int64(size) * 8); err != nil{
+				return err
+			}
+
+			newArr := make([]object.Object, 0, size)
+			for _, arg := range args{
+				newArr = append(newArr, arg.(*object.Array).Elements...)
+			}
+
+			return &object.Array{Elements: newArr}
+		},
+	},
+	"reverse": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("reverse", args[0])
+			if err != nil{
+				return err
+			}
+
+			sz := len(arr.Elements)
+			newArr := make([]object.Object, sz)
+			for i, el := range arr.Elements{
+				newArr[sz-1-i] = el
+			}
+
+			return &object.Array{Elements: newArr}
+		},
+	},
+	"slice": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2 && len(args)!=3{
+				return newError("wrong number of args, expected=2 or 3, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("slice", args[0])
+			if err != nil{
+				return err
+			}
+
+			length := int64(len(arr.Elements))
+			start, err := integerArg("slice", args[1])
+			if err != nil{
+				return err
+			}
+
+			end := length
+			if len(args) == 3{
+				if end, err = integerArg("slice", args[2]); err != nil{
+					return err
+				}
+			}
+
+			start, end = clampSliceBound(start, length), clampSliceBound(end, length)
+			if end < start{
+				end = start
+			}
+
+			return copyArray(arr.Elements[start:end])
+		},
+	},
+	"flatten": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("flatten", args[0])
+			if err != nil{
+				return err
+			}
+
+			//flattens one level unless told otherwise
+			depth := int64(1)
+			if len(args) == 2{
+				if depth, err = integerArg("flatten", args[1]); err != nil{
+					return err
+				}
+			}
+
+			flat := []object.Object{}
+			visited := 0
+			if err := flattenElements(rt, arr.Elements, depth, &flat, &visited); err != nil{
+				return err
+			}
+
+			return &object.Array{Elements: flat}
+		},
+	},
+	"unique": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("unique", args[0])
+			if err != nil{
+				return err
+			}
+
+			//hashable values are tracked in a hash, anything else falls back to a linear scan
+			seen := object.NewHash()
+			newArr := []object.Object{}
+			for _, el := range arr.Elements{
+				if _, ok := object.HashKeyOf(el); ok{
+					if _, dup := seen.Get(el); dup{
+						continue
+					}
+					seen.Set(el, TRUE)
+				}else if arrayIndexOf(&object.Array{Elements: newArr}, el) >= 0{
+					continue
+				}
+
+				newArr = append(newArr, el)
+			}
+
+			return &object.Array{Elements: newArr}
+		},
+	},
+	"sum": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			arr, err := arrayArg("sum", args[0])
+			if err != nil{
+				return err
+			}
+
+			var total int64
+			for i, el := range arr.Elements{
+				integer, ok := el.(*object.Integer)
+				if !ok{
+					return newError("sum expects an array of integers, got %s at index %d", el.Type(), i)
+				}
+				total += integer.Value
+			}
+
+			return &object.Integer{Value: total}
+		},
+	},
+}
+
+//position of the first element structurally equal to the value, -1 if there is none
+func arrayIndexOf(arr *object.Array, value object.Object) int{
+	for i, el := range arr.Elements{
+		if object.Equal(el, value){
+			return i
+		}
+	}
+
+	return -1
+}
+
+//appends the elements to flat, visited counts the elements seen across all levels for the cancellation
+//and budget checks. nested arrays can share elements, so the result can be far bigger than the input
+func flattenElements(rt object.Runtime, elements []object.Object, depth int64, flat *[]object.Object, visited *int) *object.Error{
+	for _, el := range elements{
+		*visited++
+		if err := cancelled(rt, *visited); err != nil{
+			return err
+		}
+		if *visited%cancelCheckInterval == 0{
+			if err := rt.Reserve(int64(len(*flat)) * 8); err != nil{
+				return err
+			}
+		}
+
+		if inner, ok := el.(*object.Array); ok && depth > 0{
+			if err := flattenElements(rt, inner.Elements, depth-1, flat, visited); err != nil{
+				return err
+			}
+			continue
+		}
+		*flat = append(*flat, el)
+	}
+
+	return nil
+}
+
+func copyArray(elements []object.Object) *object.Array{
+	newArr := make([]object.Object, len(elements))
+	copy(newArr, elements)
+
+	return &object.Array{Elements: newArr}
+}
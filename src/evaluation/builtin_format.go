@@ -0,0 +1,182 @@
+package evaluation
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(formatBuiltins)
+}
+
+var formatBuiltins = map[string]*object.Builtin{
+	"str": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			return &object.String{Value: stringify(args[0])}
+		},
+	},
+	"format": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) < 1{
+				return newError("wrong number of args, expected at least 1, got=%d", len(args))
+			}
+
+			format, err := stringArg("format", args[0])
+			if err != nil{
+				return err
+			}
+
+			formatted, err := formatString(rt, format, args[1:])
+			if err != nil{
+				return err
+			}
+
+			return &object.String{Value: formatted}
+		},
+	},
+}
+
+//the text a value turns into inside format, str and interpolated strings
+func stringify(obj object.Object) string{
+	if str, ok := obj.(*object.String); ok{
+		return str.Value
+	}
+
+	return obj.Inspect()
+}
+
+//replaces every `{}` with the next argument, `{n}` picks the nth argument and `{:spec}` pads it.
+//`{{` and `}}` stand for literal braces
+func formatString(rt object.Runtime, format string, args []object.Object) (string, *object.Error){
+	runes := []rune(format)
+
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(runes); i++{
+		switch{
+		case runes[i] == '}':
+			if i+1 < len(runes) && runes[i+1] == '}'{
+				out.WriteRune('}')
+				i++
+				continue
+			}
+			return "", newError("format has a single } at position %d, use }} for a literal brace", i)
+		case runes[i] != '{':
+			out.WriteRune(runes[i])
+			continue
+		case i+1 < len(runes) && runes[i+1] == '{':
+			out.WriteRune('{')
+			i++
+			continue
+		}
+
+		end := i+1
+		for end < len(runes) && runes[end] != '}'{
+			end++
+		}
+		if end == len(runes){
+			return "", newError("format has an unclosed { at position %d", i)
+		}
+
+		field := string(runes[i+1 : end])
+		index, spec, _ := strings.Cut(field, ":")
+
+		argIdx := next
+		if index == ""{
+			next++
+		}else{
+			n, convErr := strconv.Atoi(index)
+			if convErr != nil || n < 0{
+				return "", newError("invalid format placeholder {%s}", field)
+			}
+			argIdx = n
+		}
+
+		if argIdx >= len(args){
+			return "", newError("format placeholder {%s} has no matching argument, got %d args", field, len(args))
+		}
+
+		formatted, err := applyFormatSpec(rt, args[argIdx], spec)
+		if err != nil{
+			return "", err
+		}
+
+		out.WriteString(formatted)
+		i = end
+	}
+
+	return out.String(), nil
+}
+
+//spec is `[[fill]align][0][width]` with align one of < > ^, numbers align right and everything else left by default
+func applyFormatSpec(rt object.Runtime, obj object.Object, spec string) (string, *object.Error){
+	str := stringify(obj)
+	if spec == ""{
+		return str, nil
+	}
+
+	specRunes := []rune(spec)
+	fill, align := ' ', rune(0)
+	switch{
+	case len(specRunes) >= 2 && strings.ContainsRune("<>^", specRunes[1]):
+		fill, align = specRunes[0], specRunes[1]
+		specRunes = specRunes[2:]
+	case strings.ContainsRune("<>^", specRunes[0]):
+		align = specRunes[0]
+		specRunes = specRunes[1:]
+	}
+
+	zeroPad := align == 0 && len(specRunes) > 0 && specRunes[0] == '0'
+	if zeroPad{
+		fill, align = '0', '>'
+	}
+
+	width := 0
+	if len(specRunes) > 0{
+		n, err := strconv.Atoi(string(specRunes))
+		if err != nil || n < 0{
+			return "", newError("invalid format spec %q", spec)
+		}
+		if n > maxBuiltinResult{
+			return "", newError("format width %d exceeds the limit of %d", n, maxBuiltinResult)
+		}
+		width = n
+	}
+
+	if align == 0{
+		align = '<'
+		if obj.Type() == object.INTEGER_VAL{
+			align = '>'
+		}
+	}
+
+	padding := width - utf8.RuneCountInString(str)
+	if padding <= 0{
+		return str, nil
+	}
+	if err := rt.Reserve(int64(padding * utf8.RuneLen(fill))); err != nil{
+		return "", err
+	}
+
+	//the zeros go between the sign and the digits, -0042 and not 00-42
+	if zeroPad && obj.Type() == object.INTEGER_VAL && strings.HasPrefix(str, "-"){
+		return "-" + strings.Repeat("0", padding) + str[1:], nil
+	}
+
+	switch align{
+	case '<':
+		return str + strings.Repeat(string(fill), padding), nil
+	case '>':
+		return strings.Repeat(string(fill), padding) + str, nil
+	default:
+		left := padding/2
+		return strings.Repeat(string(fill), left) + str + strings.Repeat(string(fill), padding-left), nil
+	}
+}
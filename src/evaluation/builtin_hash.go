@@ -0,0 +1,122 @@
+package evaluation
+
+import (
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(hashBuiltins)
+}
+
+//hash builtins never modify their arguments, arrays come back in the iteration order of the hash
+var hashBuiltins = map[string]*object.Builtin{
+	"keys": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return mapHashPairs("keys", args, func(pair object.HashPair) object.Object{
+				return pair.Key
+			})
+		},
+	},
+	"values": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return mapHashPairs("values", args, func(pair object.HashPair) object.Object{
+				return pair.Value
+			})
+		},
+	},
+	"entries": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return mapHashPairs("entries", args, func(pair object.HashPair) object.Object{
+				return &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+			})
+		},
+	},
+	"has": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			hash, err := hashArg("has", args[0])
+			if err != nil{
+				return err
+			}
+
+			if _, ok := object.HashKeyOf(args[1]); !ok{
+				return newError("the key is not usable as hashkey , got=%s", args[1].Type())
+			}
+
+			_, ok := hash.Get(args[1])
+			return evaluateBoolean(ok)
+		},
+	},
+	"delete": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) < 2{
+				return newError("wrong number of args, expected at least 2, got=%d", len(args))
+			}
+
+			hash, err := hashArg("delete", args[0])
+			if err != nil{
+				return err
+			}
+
+			removed := object.NewHash()
+			for _, key := range args[1:]{
+				if !removed.Set(key, TRUE){
+					return newError("the key is not usable as hashkey , got=%s", key.Type())
+				}
+			}
+
+			result := object.NewHash()
+			for _, pair := range hash.Pairs(){
+				if _, ok := removed.Get(pair.Key); !ok{
+					result.Set(pair.Key, pair.Value)
+				}
+			}
+
+			return result
+		},
+	},
+	"merge": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) < 1{
+				return newError("wrong number of args, expected at least 1, got=%d", len(args))
+			}
+
+			//later hashes win on conflicting keys, a key keeps the position it was first seen at
+			result := object.NewHash()
+			for _, arg := range args{
+				hash, err := hashArg("merge", arg)
+				if err != nil{
+					return err
+				}
+
+				for _, pair := range hash.Pairs(){
+					result.Set(pair.Key, pair.Value)
+				}
+			}
+
+			return result
+		},
+	},
+}
+
+func mapHashPairs(name string, args []object.Object, fn func(object.HashPair) object.Object) object.Object{
+	if len(args)!=1{
+		return newError("wrong number of args, expected=1, got=%d", len(args))
+	}
+
+	hash, err := hashArg(name, args[0])
+	if err != nil{
+		return err
+	}
+
+	pairs := hash.Pairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs{
+		elements[i] = fn(pair)
+	}
+
+	return &object.Array{Elements: elements}
+}
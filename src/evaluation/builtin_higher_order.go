@@ -0,0 +1,361 @@
+package evaluation
+
+import (
+	"sort"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(higherOrderBuiltins)
+}
+
+var higherOrderBuiltins = map[string]*object.Builtin{
+	"map": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			arr, fn, err := arrayAndCallback("map", args[0], args[1])
+			if err != nil{
+				return err
+			}
+
+			result := make([]object.Object, 0, len(arr.Elements))
+			for i, el := range arr.Elements{
+				if err := cancelled(rt, i); err != nil{
+					return err
+				}
+
+				mapped := rt.Call(fn, el)
+				if isError(mapped){
+					return mapped
+				}
+				result = append(result, mapped)
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"filter": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			arr, fn, err := arrayAndCallback("filter", args[0], args[1])
+			if err != nil{
+				return err
+			}
+
+			result := []object.Object{}
+			for i, el := range arr.Elements{
+				if err := cancelled(rt, i); err != nil{
+					return err
+				}
+
+				keep := rt.Call(fn, el)
+				if isError(keep){
+					return keep
+				}
+				if isTruthful(keep){
+					result = append(result, el)
+				}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"reduce": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2 && len(args)!=3{
+				return newError("wrong number of args, expected=2 or 3, got=%d", len(args))
+			}
+
+			arr, fn, err := arrayAndCallback("reduce", args[0], args[1])
+			if err != nil{
+				return err
+			}
+
+			elements := arr.Elements
+			var acc object.Object
+			if len(args) == 3{
+				acc = args[2]
+			}else{
+				if len(elements) == 0{
+					return newError("reduce of an empty array needs an initial value")
+				}
+				acc = elements[0]
+				elements = elements[1:]
+			}
+
+			for _, el := range elements{
+				acc = rt.Call(fn, acc, el)
+				if isError(acc){
+					return acc
+				}
+			}
+
+			return acc
+		},
+	},
+	"sort": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			arr, ok := args[0].(*object.Array)
+			if !ok{
+				return newError("argument for the sort builtin not supported, got %s", args[0].Type())
+			}
+
+			sorted := make([]object.Object, len(arr.Elements))
+			copy(sorted, arr.Elements)
+
+			if len(args) == 1{
+				return sortNatural(rt, sorted)
+			}
+
+			if !isCallable(args[1]){
+				return newError("argument for the sort builtin not supported, expected a function, got %s", args[1].Type())
+			}
+
+			//the comparator returns either a boolean (a < b) or an integer (negative when a < b)
+			var failure object.Object
+			sort.SliceStable(sorted, func(i, j int) bool{
+				if failure != nil{
+					return false
+				}
+
+				switch res := rt.Call(args[1], sorted[i], sorted[j]).(type){
+				case *object.Boolean:
+					return res.Value
+				case *object.Integer:
+					return res.Value < 0
+				case *object.Error:
+					failure = res
+				default:
+					failure = newError("sort comparator has to return a boolean or an integer, got %s", res.Type())
+				}
+				return false
+			})
+
+			if failure != nil{
+				return failure
+			}
+
+			return &object.Array{Elements: sorted}
+		},
+	},
+	"zip": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) < 2{
+				return newError("wrong number of args, expected at least 2, got=%d", len(args))
+			}
+
+			arrays := make([]*object.Array, len(args))
+			shortest := -1
+			for i, arg := range args{
+				arr, ok := arg.(*object.Array)
+				if !ok{
+					return newError("argument for the zip builtin not supported, got %s", arg.Type())
+				}
+
+				arrays[i] = arr
+				if shortest == -1 || len(arr.Elements) < shortest{
+					shortest = len(arr.Elements)
+				}
+			}
+
+			result := make([]object.Object, shortest)
+			for i := 0; i < shortest; i++{
+				tuple := make([]object.Object, len(arrays))
+				for j, arr := range arrays{
+					tuple[j] = arr.Elements[i]
+				}
+				result[i] = &object.Array{Elements: tuple}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"range": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) < 1 || len(args) > 3{
+				return newError("wrong number of args, expected=1, 2 or 3, got=%d", len(args))
+			}
+
+			bounds := make([]int64, len(args))
+			for i, arg := range args{
+				integer, ok := arg.(*object.Integer)
+				if !ok{
+					return newError("argument for the range builtin not supported, got %s", arg.Type())
+				}
+				bounds[i] = integer.Value
+			}
+
+			start, end, step := int64(0), bounds[0], int64(1)
+			if len(bounds) > 1{
+				start, end = bounds[0], bounds[1]
+			}
+			if len(bounds) > 2{
+				step = bounds[2]
+			}
+
+			if step == 0{
+				return newError("range step cannot be zero")
+			}
+
+			count := rangeLength(start, end, step)
+			if count > maxBuiltinResult{
+				return newError("range of %d elements exceeds the limit of %d", count, maxBuiltinResult)
+			}
+			if err := rt.Reserve(int64(count) * 8); err != nil{
+				return err
+			}
+
+			result := make([]object.Object, count)
+			for i := range result{
+				if err := cancelled(rt, i); err != nil{
+					return err
+				}
+				result[i] = &object.Integer{Value: start + int64(i)*step}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"any": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return evalPredicate(rt, "any", true, args)
+		},
+	},
+	"all": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return evalPredicate(rt, "all", false, args)
+		},
+	},
+}
+
+//the number of elements from start towards end, computed unsigned so a distance or step near the int64 limits cannot wrap
+func rangeLength(start, end, step int64) uint64{
+	if (step > 0 && start >= end) || (step < 0 && start <= end){
+		return 0
+	}
+
+	distance, stride := uint64(end)-uint64(start), uint64(step)
+	if step < 0{
+		distance, stride = uint64(start)-uint64(end), -uint64(step)
+	}
+
+	return (distance-1)/stride + 1
+}
+
+//any stops at the first truthy result, all at the first falsy one. without a predicate the elements themselves are tested
+func evalPredicate(rt object.Runtime, name string, stopOn bool, args []object.Object) object.Object{
+	if len(args)!=1 && len(args)!=2{
+		return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok{
+		return newError("argument for the %s builtin not supported, got %s", name, args[0].Type())
+	}
+
+	if len(args) == 2 && !isCallable(args[1]){
+		return newError("argument for the %s builtin not supported, expected a function, got %s", name, args[1].Type())
+	}
+
+	for _, el := range arr.Elements{
+		res := el
+		if len(args) == 2{
+			res = rt.Call(args[1], el)
+			if isError(res){
+				return res
+			}
+		}
+
+		if isTruthful(res) == stopOn{
+			return evaluateBoolean(stopOn)
+		}
+	}
+
+	return evaluateBoolean(!stopOn)
+}
+
+func arrayAndCallback(name string, arr, fn object.Object) (*object.Array, object.Object, *object.Error){
+	array, ok := arr.(*object.Array)
+	if !ok{
+		return nil, nil, newError("argument for the %s builtin not supported, got %s", name, arr.Type())
+	}
+
+	if !isCallable(fn){
+		return nil, nil, newError("argument for the %s builtin not supported, expected a function, got %s", name, fn.Type())
+	}
+
+	return array, fn, nil
+}
+
+//sorts integers or strings in ascending order when no comparator is given
+func sortNatural(rt object.Runtime, elements []object.Object) object.Object{
+	if len(elements) == 0{
+		return &object.Array{Elements: elements}
+	}
+
+	var less func(i, j int) bool
+	switch elements[0].(type){
+	case *object.Integer:
+		for _, el := range elements{
+			if _, ok := el.(*object.Integer); !ok{
+				return newError("sort without a comparator needs all integers or all strings, got %s", el.Type())
+			}
+		}
+		less = func(i, j int) bool{
+			return elements[i].(*object.Integer).Value < elements[j].(*object.Integer).Value
+		}
+	case *object.String:
+		for _, el := range elements{
+			if _, ok := el.(*object.String); !ok{
+				return newError("sort without a comparator needs all integers or all strings, got %s", el.Type())
+			}
+		}
+		less = func(i, j int) bool{
+			return elements[i].(*object.String).Value < elements[j].(*object.String).Value
+		}
+	default:
+		return newError("sort without a comparator needs all integers or all strings, got %s", elements[0].Type())
+	}
+
+	//the sort cannot be stopped halfway, once cancelled the remaining comparisons are answered right away
+	var failure *object.Error
+	comparisons := 0
+	sort.SliceStable(elements, func(i, j int) bool{
+		if failure != nil{
+			return false
+		}
+
+		comparisons++
+		if failure = cancelled(rt, comparisons); failure != nil{
+			return false
+		}
+
+		return less(i, j)
+	})
+
+	if failure != nil{
+		return failure
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func isCallable(obj object.Object) bool{
+	switch obj.(type){
+	case *object.Function, *object.Builtin:
+		return true
+	default:
+		return false
+	}
+}
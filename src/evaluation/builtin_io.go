@@ -0,0 +1,97 @@
+package evaluation
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerCapability(CapIO, ioBuiltins)
+	registerCapability(CapFS, fsBuiltins)
+}
+
+var ioBuiltins = map[string]*object.Builtin{
+	"print": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			for _, arg := range args{
+				fmt.Fprintln(rt.Output(), arg.Inspect())
+			}
+
+			return NULL
+		},
+	},
+	"input": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) > 1{
+				return newError("wrong number of args, expected=0 or 1, got=%d", len(args))
+			}
+
+			if len(args) == 1{
+				prompt, err := stringArg("input", args[0])
+				if err != nil{
+					return err
+				}
+				fmt.Fprint(rt.Output(), prompt)
+			}
+
+			//null tells the script the input has run out
+			line, err := rt.ReadLine()
+			if err == io.EOF{
+				return NULL
+			}
+			if err != nil{
+				return newError("could not read input: %s", err)
+			}
+
+			return &object.String{Value: line}
+		},
+	},
+}
+
+var fsBuiltins = map[string]*object.Builtin{
+	"read_file": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			path, err := stringArg("read_file", args[0])
+			if err != nil{
+				return err
+			}
+
+			content, readErr := os.ReadFile(path)
+			if readErr != nil{
+				return newError("could not read file: %s", readErr)
+			}
+
+			return &object.String{Value: string(content)}
+		},
+	},
+	"write_file": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			path, err := stringArg("write_file", args[0])
+			if err != nil{
+				return err
+			}
+
+			content, err := stringArg("write_file", args[1])
+			if err != nil{
+				return err
+			}
+
+			if writeErr := os.WriteFile(path, []byte(content), 0644); writeErr != nil{
+				return newError("could not write file: %s", writeErr)
+			}
+
+			return NULL
+		},
+	},
+}
@@ -0,0 +1,239 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(jsonBuiltins)
+}
+
+var jsonBuiltins = map[string]*object.Builtin{
+	"json_parse": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			str, err := stringArg("json_parse", args[0])
+			if err != nil{
+				return err
+			}
+
+			dec := json.NewDecoder(strings.NewReader(str))
+			dec.UseNumber()
+
+			value, decodeErr := decodeJSON(dec)
+			if decodeErr != nil{
+				return newError("invalid json: %s", decodeErr)
+			}
+
+			if _, trailing := dec.Token(); trailing != io.EOF{
+				return newError("invalid json: unexpected data after the top level value")
+			}
+
+			return value
+		},
+	},
+	"json_stringify": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			var out bytes.Buffer
+			if err := encodeJSON(&out, args[0]); err != nil{
+				return err
+			}
+
+			if len(args) == 1{
+				return &object.String{Value: out.String()}
+			}
+
+			//the indent is either a number of spaces or the string to indent with
+			var indent string
+			switch arg := args[1].(type){
+			case *object.Integer:
+				if arg.Value < 0{
+					return newError("json indent cannot be negative, got=%d", arg.Value)
+				}
+				if arg.Value > maxBuiltinResult{
+					return newError("json indent of %d exceeds the limit of %d", arg.Value, maxBuiltinResult)
+				}
+				indent = strings.Repeat(" ", int(arg.Value))
+			case *object.String:
+				indent = arg.Value
+			default:
+				return newError("argument for the json_stringify builtin not supported, got %s", args[1].Type())
+			}
+
+			if err := rt.Reserve(indentedSize(out.Bytes(), len(indent))); err != nil{
+				return err
+			}
+
+			var indented bytes.Buffer
+			json.Indent(&indented, out.Bytes(), "", indent)
+			return &object.String{Value: indented.String()}
+		},
+	},
+}
+
+//an upper bound for the length of the compact json once indented, every bracket and comma
+//can start a new line indented by the nesting depth
+func indentedSize(compact []byte, indent int) int64{
+	size, depth := int64(len(compact)), int64(0)
+	inString, escaped := false, false
+	for _, c := range compact{
+		switch{
+		case inString:
+			switch{
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		case c == '"':
+			inString = true
+			continue
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		case c == ':':
+			size++
+			continue
+		case c != ',':
+			continue
+		}
+
+		size += 1 + depth*int64(indent)
+	}
+
+	return size
+}
+
+//walks the token stream instead of unmarshalling into a go map so object keys keep their order
+func decodeJSON(dec *json.Decoder) (object.Object, error){
+	tok, err := dec.Token()
+	if err != nil{
+		return nil, err
+	}
+
+	switch tok := tok.(type){
+	case json.Delim:
+		switch tok{
+		case '{':
+			hash := object.NewHash()
+			for dec.More(){
+				key, err := dec.Token()
+				if err != nil{
+					return nil, err
+				}
+
+				value, err := decodeJSON(dec)
+				if err != nil{
+					return nil, err
+				}
+
+				hash.Set(&object.String{Value: key.(string)}, value)
+			}
+
+			if _, err := dec.Token(); err != nil{
+				return nil, err
+			}
+			return hash, nil
+		case '[':
+			elements := []object.Object{}
+			for dec.More(){
+				value, err := decodeJSON(dec)
+				if err != nil{
+					return nil, err
+				}
+				elements = append(elements, value)
+			}
+
+			if _, err := dec.Token(); err != nil{
+				return nil, err
+			}
+			return &object.Array{Elements: elements}, nil
+		default:
+			return nil, fmt.Errorf("unexpected %s", tok)
+		}
+	case json.Number:
+		val, err := tok.Int64()
+		if err != nil{
+			return nil, fmt.Errorf("number %s is not an integer", tok)
+		}
+		return &object.Integer{Value: val}, nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case bool:
+		return evaluateBoolean(tok), nil
+	default:
+		return NULL, nil
+	}
+}
+
+func encodeJSON(out *bytes.Buffer, obj object.Object) *object.Error{
+	switch obj := obj.(type){
+	case *object.Integer:
+		out.WriteString(obj.Inspect())
+	case *object.Boolean:
+		out.WriteString(obj.Inspect())
+	case *object.Null:
+		out.WriteString("null")
+	case *object.String:
+		encodeJSONString(out, obj.Value)
+	case *object.Array:
+		out.WriteString("[")
+		for i, el := range obj.Elements{
+			if i > 0{
+				out.WriteString(",")
+			}
+			if err := encodeJSON(out, el); err != nil{
+				return err
+			}
+		}
+		out.WriteString("]")
+	case *object.Hash:
+		out.WriteString("{")
+		for i, pair := range obj.Pairs(){
+			key, ok := pair.Key.(*object.String)
+			if !ok{
+				return newError("json object keys have to be strings, got %s", pair.Key.Type())
+			}
+
+			if i > 0{
+				out.WriteString(",")
+			}
+			encodeJSONString(out, key.Value)
+			out.WriteString(":")
+			if err := encodeJSON(out, pair.Value); err != nil{
+				return err
+			}
+		}
+		out.WriteString("}")
+	default:
+		return newError("cannot convert %s to json", obj.Type())
+	}
+
+	return nil
+}
+
+func encodeJSONString(out *bytes.Buffer, str string){
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(str)
+
+	//the encoder terminates every value with a newline
+	out.Truncate(out.Len()-1)
+}
@@ -0,0 +1,227 @@
+package evaluation
+
+import (
+	"math"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(mathBuiltins)
+}
+
+//math builtins switch on the numeric type so another number type can slot in next to integers
+var mathBuiltins = map[string]*object.Builtin{
+	"abs": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			switch arg := args[0].(type){
+			case *object.Integer:
+				if arg.Value == math.MinInt64{
+					return newError("integer overflow in abs(%d)", arg.Value)
+				}
+				if arg.Value < 0{
+					return &object.Integer{Value: -arg.Value}
+				}
+				return arg
+			default:
+				return newError("argument for the abs builtin not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	"min": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return pickInteger("min", args, func(a, b int64) bool{ return a < b })
+		},
+	},
+	"max": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return pickInteger("max", args, func(a, b int64) bool{ return a > b })
+		},
+	},
+	"pow": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			base, err := integerArg("pow", args[0])
+			if err != nil{
+				return err
+			}
+
+			exp, err := integerArg("pow", args[1])
+			if err != nil{
+				return err
+			}
+
+			if exp < 0{
+				return newError("negative exponent not supported for integers, got pow(%d, %d)", base, exp)
+			}
+
+			result, ok := integerPow(base, exp)
+			if !ok{
+				return newError("integer overflow in pow(%d, %d)", base, exp)
+			}
+
+			return &object.Integer{Value: result}
+		},
+	},
+	"sqrt": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			switch arg := args[0].(type){
+			case *object.Integer:
+				if arg.Value < 0{
+					return newError("sqrt of a negative number, got %d", arg.Value)
+				}
+				return &object.Integer{Value: integerSqrt(arg.Value)}
+			default:
+				return newError("argument for the sqrt builtin not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	"floor": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return roundNumber("floor", args)
+		},
+	},
+	"ceil": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return roundNumber("ceil", args)
+		},
+	},
+	"round": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return roundNumber("round", args)
+		},
+	},
+	"clamp": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=3{
+				return newError("wrong number of args, expected=3, got=%d", len(args))
+			}
+
+			bounds := make([]int64, 3)
+			for i, arg := range args{
+				val, err := integerArg("clamp", arg)
+				if err != nil{
+					return err
+				}
+				bounds[i] = val
+			}
+
+			val, low, high := bounds[0], bounds[1], bounds[2]
+			if low > high{
+				return newError("clamp lower bound is greater than the upper bound, got low=%d, high=%d", low, high)
+			}
+
+			if val < low{
+				return &object.Integer{Value: low}
+			}
+			if val > high{
+				return &object.Integer{Value: high}
+			}
+			return args[0]
+		},
+	},
+}
+
+//min and max take either the values themselves or a single array of them
+func pickInteger(name string, args []object.Object, better func(a, b int64) bool) object.Object{
+	if len(args) == 1{
+		if arr, ok := args[0].(*object.Array); ok{
+			args = arr.Elements
+		}
+	}
+
+	if len(args) == 0{
+		return newError("%s needs at least one value", name)
+	}
+
+	var best *object.Integer
+	for _, arg := range args{
+		integer, ok := arg.(*object.Integer)
+		if !ok{
+			return newError("argument for the %s builtin not supported, got %s", name, arg.Type())
+		}
+
+		if best == nil || better(integer.Value, best.Value){
+			best = integer
+		}
+	}
+
+	return best
+}
+
+//integers are already whole, so rounding them is the identity
+func roundNumber(name string, args []object.Object) object.Object{
+	if len(args)!=1{
+		return newError("wrong number of args, expected=1, got=%d", len(args))
+	}
+
+	switch arg := args[0].(type){
+	case *object.Integer:
+		return arg
+	default:
+		return newError("argument for the %s builtin not supported, got %s", name, args[0].Type())
+	}
+}
+
+//exponentiation by squaring, reports false when the result does not fit in an int64
+func integerPow(base, exp int64) (int64, bool){
+	result := int64(1)
+	for exp > 0{
+		if exp&1 == 1{
+			next, ok := multiplyChecked(result, base)
+			if !ok{
+				return 0, false
+			}
+			result = next
+		}
+
+		exp >>= 1
+		if exp > 0{
+			next, ok := multiplyChecked(base, base)
+			if !ok{
+				return 0, false
+			}
+			base = next
+		}
+	}
+
+	return result, true
+}
+
+func multiplyChecked(a, b int64) (int64, bool){
+	if a == 0 || b == 0{
+		return 0, true
+	}
+
+	result := a * b
+	if result/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64){
+		return 0, false
+	}
+
+	return result, true
+}
+
+//square root rounded down
+func integerSqrt(n int64) int64{
+	root := int64(math.Sqrt(float64(n)))
+	//compared by dividing, squaring a root near 3037000499 overflows int64
+	for root > 0 && root > n/root{
+		root--
+	}
+	for root+1 <= n/(root+1){
+		root++
+	}
+
+	return root
+}
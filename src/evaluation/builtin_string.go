@@ -0,0 +1,322 @@
+package evaluation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerBuiltins(stringBuiltins)
+}
+
+//string builtins work on runes, same as the lexer, so indexes and lengths count characters not bytes
+var stringBuiltins = map[string]*object.Builtin{
+	"split": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			str, err := stringArg("split", args[0])
+			if err != nil{
+				return err
+			}
+
+			//without a separator split on whitespace
+			var parts []string
+			if len(args) == 1{
+				parts = strings.Fields(str)
+			}else{
+				sep, err := stringArg("split", args[1])
+				if err != nil{
+					return err
+				}
+				parts = strings.Split(str, sep)
+			}
+
+			return stringArray(parts)
+		},
+	},
+	"join": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			arr, ok := args[0].(*object.Array)
+			if !ok{
+				return newError("argument for the join builtin not supported, got %s", args[0].Type())
+			}
+
+			sep := ""
+			if len(args) == 2{
+				var err *object.Error
+				if sep, err = stringArg("join", args[1]); err != nil{
+					return err
+				}
+			}
+
+			parts := make([]string, len(arr.Elements))
+			for i, el := range arr.Elements{
+				if err := cancelled(rt, i); err != nil{
+					return err
+				}
+
+				str, ok := el.(*object.String)
+				if !ok{
+					return newError("join expects an array of strings, got %s at index %d", el.Type(), i)
+				}
+				parts[i] = str.Value
+			}
+
+			return &object.String{Value: strings.Join(parts, sep)}
+		},
+	},
+	"trim": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			str, err := stringArg("trim", args[0])
+			if err != nil{
+				return err
+			}
+
+			if len(args) == 1{
+				return &object.String{Value: strings.TrimSpace(str)}
+			}
+
+			cutset, err := stringArg("trim", args[1])
+			if err != nil{
+				return err
+			}
+
+			return &object.String{Value: strings.Trim(str, cutset)}
+		},
+	},
+	"upper": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return mapString("upper", args, strings.ToUpper)
+		},
+	},
+	"lower": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return mapString("lower", args, strings.ToLower)
+		},
+	},
+	"contains": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args) == 2{
+				if arr, ok := args[0].(*object.Array); ok{
+					return evaluateBoolean(arrayIndexOf(arr, args[1]) >= 0)
+				}
+			}
+
+			return matchStrings("contains", args, strings.Contains)
+		},
+	},
+	"starts_with": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return matchStrings("starts_with", args, strings.HasPrefix)
+		},
+	},
+	"ends_with": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			return matchStrings("ends_with", args, strings.HasSuffix)
+		},
+	},
+	"replace": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=3 && len(args)!=4{
+				return newError("wrong number of args, expected=3 or 4, got=%d", len(args))
+			}
+
+			strs := make([]string, 3)
+			for i := range strs{
+				str, err := stringArg("replace", args[i])
+				if err != nil{
+					return err
+				}
+				strs[i] = str
+			}
+
+			//replaces every occurrence unless a count is given
+			count := int64(-1)
+			if len(args) == 4{
+				var err *object.Error
+				if count, err = integerArg("replace", args[3]); err != nil{
+					return err
+				}
+			}
+
+			return &object.String{Value: strings.Replace(strs[0], strs[1], strs[2], int(count))}
+		},
+	},
+	"index_of": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			if arr, ok := args[0].(*object.Array); ok{
+				return &object.Integer{Value: int64(arrayIndexOf(arr, args[1]))}
+			}
+
+			str, err := stringArg("index_of", args[0])
+			if err != nil{
+				return err
+			}
+
+			sub, err := stringArg("index_of", args[1])
+			if err != nil{
+				return err
+			}
+
+			idx := strings.Index(str, sub)
+			if idx < 0{
+				return &object.Integer{Value: -1}
+			}
+
+			return &object.Integer{Value: int64(utf8.RuneCountInString(str[:idx]))}
+		},
+	},
+	"repeat": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2{
+				return newError("wrong number of args, expected=2, got=%d", len(args))
+			}
+
+			str, err := stringArg("repeat", args[0])
+			if err != nil{
+				return err
+			}
+
+			count, err := integerArg("repeat", args[1])
+			if err != nil{
+				return err
+			}
+
+			if count < 0{
+				return newError("repeat count cannot be negative, got=%d", count)
+			}
+
+			if str == ""{
+				return &object.String{Value: ""}
+			}
+
+			//checked by dividing, the product itself can overflow
+			if count > maxBuiltinResult/int64(len(str)){
+				return newError("repeat result of %d x %d bytes exceeds the limit of %d", count, len(str), maxBuiltinResult)
+			}
+			if err := rt.Reserve(count * int64(len(str))); err != nil{
+				return err
+			}
+
+			//built piece by piece instead of strings.Repeat so a cancelled evaluation stops it
+			var out strings.Builder
+			out.Grow(len(str)*int(count))
+			for i := 0; i < int(count); i++{
+				if err := cancelled(rt, i); err != nil{
+					return err
+				}
+				out.WriteString(str)
+			}
+
+			return &object.String{Value: out.String()}
+		},
+	},
+	"chars": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			str, err := stringArg("chars", args[0])
+			if err != nil{
+				return err
+			}
+
+			runes := []rune(str)
+			chars := make([]object.Object, len(runes))
+			for i, r := range runes{
+				chars[i] = &object.String{Value: string(r)}
+			}
+
+			return &object.Array{Elements: chars}
+		},
+	},
+	"substr": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=2 && len(args)!=3{
+				return newError("wrong number of args, expected=2 or 3, got=%d", len(args))
+			}
+
+			str, err := stringArg("substr", args[0])
+			if err != nil{
+				return err
+			}
+
+			start, err := integerArg("substr", args[1])
+			if err != nil{
+				return err
+			}
+
+			runes := []rune(str)
+			length := int64(len(runes)) - start
+			if len(args) == 3{
+				if length, err = integerArg("substr", args[2]); err != nil{
+					return err
+				}
+			}
+
+			if start < 0 || start > int64(len(runes)) || length < 0 || length > int64(len(runes))-start{
+				return newError("substr out of bound, string length=%d, got start=%d, length=%d", len(runes), start, length)
+			}
+
+			return &object.String{Value: string(runes[start : start+length])}
+		},
+	},
+}
+
+func mapString(name string, args []object.Object, fn func(string) string) object.Object{
+	if len(args)!=1{
+		return newError("wrong number of args, expected=1, got=%d", len(args))
+	}
+
+	str, err := stringArg(name, args[0])
+	if err != nil{
+		return err
+	}
+
+	return &object.String{Value: fn(str)}
+}
+
+func matchStrings(name string, args []object.Object, fn func(string, string) bool) object.Object{
+	if len(args)!=2{
+		return newError("wrong number of args, expected=2, got=%d", len(args))
+	}
+
+	str, err := stringArg(name, args[0])
+	if err != nil{
+		return err
+	}
+
+	sub, err := stringArg(name, args[1])
+	if err != nil{
+		return err
+	}
+
+	return evaluateBoolean(fn(str, sub))
+}
+
+func stringArray(strs []string) *object.Array{
+	elements := make([]object.Object, len(strs))
+	for i, str := range strs{
+		elements[i] = &object.String{Value: str}
+	}
+
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,115 @@
+package evaluation
+
+import (
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func init(){
+	registerCapability(CapTime, timeBuiltins)
+	registerCapability(CapEnv, envBuiltins)
+	registerCapability(CapRand, randBuiltins)
+}
+
+var timeBuiltins = map[string]*object.Builtin{
+	"now": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=0{
+				return newError("wrong number of args, expected=0, got=%d", len(args))
+			}
+
+			//milliseconds since the unix epoch
+			return &object.Integer{Value: time.Now().UnixMilli()}
+		},
+	},
+	"sleep": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			ms, err := integerArg("sleep", args[0])
+			if err != nil{
+				return err
+			}
+
+			if ms < 0{
+				return newError("sleep duration cannot be negative, got=%d", ms)
+			}
+
+			//wakes up early when the evaluation gets cancelled
+			timer := time.NewTimer(time.Duration(ms) * time.Millisecond)
+			defer timer.Stop()
+
+			var done <-chan struct{}
+			if ctx, ok := rt.(*Context); ok{
+				done = ctx.cancel.Done()
+			}
+
+			select{
+			case <-timer.C:
+				return NULL
+			case <-done:
+				return rt.(*Context).checkCancelled()
+			}
+		},
+	},
+}
+
+var envBuiltins = map[string]*object.Builtin{
+	"getenv": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1{
+				return newError("wrong number of args, expected=1, got=%d", len(args))
+			}
+
+			name, err := stringArg("getenv", args[0])
+			if err != nil{
+				return err
+			}
+
+			value, ok := os.LookupEnv(name)
+			if !ok{
+				return NULL
+			}
+
+			return &object.String{Value: value}
+		},
+	},
+}
+
+var randBuiltins = map[string]*object.Builtin{
+	"random": &object.Builtin{
+		Fn : func(rt object.Runtime, args ...object.Object) object.Object{
+			if len(args)!=1 && len(args)!=2{
+				return newError("wrong number of args, expected=1 or 2, got=%d", len(args))
+			}
+
+			//random(n) picks from [0, n), random(low, high) from [low, high)
+			low, high := int64(0), int64(0)
+			bounds := []*int64{&high}
+			if len(args) == 2{
+				bounds = []*int64{&low, &high}
+			}
+			for i, bound := range bounds{
+				val, err := integerArg("random", args[i])
+				if err != nil{
+					return err
+				}
+				*bound = val
+			}
+
+			if high <= low{
+				return newError("random needs a non empty range, got low=%d, high=%d", low, high)
+			}
+			if high-low < 0{
+				return newError("random range is too large, got low=%d, high=%d", low, high)
+			}
+
+			return &object.Integer{Value: low + rand.Int63n(high-low)}
+		},
+	},
+}
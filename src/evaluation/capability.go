@@ -0,0 +1,69 @@
+package evaluation
+
+import (
+	"sort"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+//a named group of builtins reaching outside the interpreter, a context only resolves them once the host enables the group
+type Capability string
+
+const (
+	CapIO   Capability = "io"
+	CapFS   Capability = "fs"
+	CapTime Capability = "time"
+	CapEnv  Capability = "env"
+	CapRand Capability = "rand"
+)
+
+var AllCapabilities = []Capability{CapIO, CapFS, CapTime, CapEnv, CapRand}
+
+var capabilityBuiltins = map[Capability]map[string]*object.Builtin{}
+
+//adds a set of builtins that are only available with the capability enabled
+func registerCapability(capability Capability, set map[string]*object.Builtin){
+	if capabilityBuiltins[capability] == nil{
+		capabilityBuiltins[capability] = map[string]*object.Builtin{}
+	}
+
+	for name, builtin := range set{
+		capabilityBuiltins[capability][name] = builtin
+	}
+}
+
+//makes the builtins of the capabilities resolvable in this context
+func (ctx *Context) Enable(capabilities ...Capability){
+	for _, capability := range capabilities{
+		ctx.capabilities[capability] = true
+		for name, builtin := range capabilityBuiltins[capability]{
+			ctx.Builtins[name] = builtin
+		}
+	}
+}
+
+func (ctx *Context) Enabled(capability Capability) bool{
+	return ctx.capabilities[capability]
+}
+
+//the enabled capabilities in a stable order
+func (ctx *Context) Capabilities() []Capability{
+	enabled := []Capability{}
+	for capability := range ctx.capabilities{
+		enabled = append(enabled, capability)
+	}
+	sort.Slice(enabled, func(i, j int) bool{ return enabled[i] < enabled[j] })
+
+	return enabled
+}
+
+//the error for a builtin of a capability the context did not enable, nil for names that are no capability builtin
+func disabledBuiltinError(name string) *object.Error{
+	for capability, set := range capabilityBuiltins{
+		if _, ok := set[name]; ok{
+			return newError("builtin %s requires capability %s, which is not enabled", name, capability)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package evaluation
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+//everything a single evaluation carries around besides the environment, it is also the runtime handed to builtins
+type Context struct{
+	Out io.Writer
+	In *bufio.Reader
+	//starts as a copy of the default builtins, so a host can add its own without touching other contexts.
+	//builtins of a capability are only added once it is enabled
+	Builtins map[string]*object.Builtin
+	capabilities map[Capability]bool
+	//checked on every function call, once it is done the evaluation unwinds with a cancellation error
+	cancel context.Context
+	//how deep user functions may call into each other, the go stack runs out long before a deadline would fire
+	MaxDepth int
+	depth int
+	Limits Limits
+	usage Usage
+	//relative imports of the top level script resolve against Dir, the loader reads the resolved path.
+	//without a loader modules are read from the file system, which needs CapFS
+	Dir string
+	Loader ModuleLoader
+	modules map[string]*object.Module
+	importing []string
+	prelude *object.Environment
+}
+
+//the call depth a new context allows
+const DefaultMaxDepth = 10000
+
+//no capability is enabled yet. a nil reader makes input always hit the end of input, a nil writer discards the output
+func NewContext(out io.Writer, in io.Reader) *Context{
+	if out == nil{
+		out = io.Discard
+	}
+
+	ctx := &Context{
+		Out: out,
+		cancel: context.Background(),
+		MaxDepth: DefaultMaxDepth,
+		Builtins: make(map[string]*object.Builtin, len(builtins)),
+		capabilities: map[Capability]bool{},
+		modules: map[string]*object.Module{},
+	}
+	for name, builtin := range builtins{
+		ctx.Builtins[name] = builtin
+	}
+
+	if in != nil{
+		//share an existing buffered reader so lines it already buffered are not lost
+		reader, ok := in.(*bufio.Reader)
+		if !ok{
+			reader = bufio.NewReader(in)
+		}
+		ctx.In = reader
+	}
+
+	return ctx
+}
+
+func (ctx *Context) Call(fn object.Object, args ...object.Object) object.Object{
+	return ctx.applyFunction(fn, args)
+}
+
+func (ctx *Context) Output() io.Writer{
+	return ctx.Out
+}
+
+func (ctx *Context) ReadLine() (string, error){
+	if ctx.In == nil{
+		return "", io.EOF
+	}
+
+	line, err := ctx.In.ReadString('\n')
+	if err != nil && (err != io.EOF || line == ""){
+		return "", err
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+//bounds the evaluation by the go context, e.g. a per request deadline
+func (ctx *Context) SetContext(c context.Context){
+	if c == nil{
+		c = context.Background()
+	}
+	ctx.cancel = c
+}
+
+func (ctx *Context) checkCancelled() *object.Error{
+	if err := ctx.cancel.Err(); err != nil{
+		return &object.Error{Message: "evaluation cancelled: " + err.Error(), Cause: err}
+	}
+
+	return nil
+}
+
+//how many iterations a builtin loop runs between two looks at the cancellation
+const cancelCheckInterval = 1024
+
+//lets a long loop inside a builtin stop once the evaluation is cancelled, it only looks every
+//cancelCheckInterval iterations. runtimes other than a Context are never cancelled
+func cancelled(rt object.Runtime, iteration int) *object.Error{
+	if iteration%cancelCheckInterval != 0{
+		return nil
+	}
+
+	ctx, ok := rt.(*Context)
+	if !ok{
+		return nil
+	}
+
+	return ctx.checkCancelled()
+}
@@ -2,113 +2,200 @@ package evaluation
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/singlaanish56/Interpreter-In-Go/ast"
 	"github.com/singlaanish56/Interpreter-In-Go/object"
 )
 
 var (
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
-	NULL  = &object.Null{}
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
+	NULL  = object.NULL
 )
 
+//evaluates with a fresh context bound to the process stdin and stdout with only io enabled,
+//use a Context to send script output elsewhere or to pick the capabilities
 func Eval(node ast.ASTNode, env *object.Environment) object.Object {
+	ctx := NewContext(os.Stdout, os.Stdin)
+	ctx.Enable(CapIO)
+
+	return ctx.Eval(node, env)
+}
+
+func (ctx *Context) Eval(node ast.ASTNode, env *object.Environment) object.Object {
+	result := ctx.evalChained(node, env)
+
+	//an optional chain that ran into null ends here, whatever uses its value sees a plain null
+	if result == shortCircuit {
+		return NULL
+	}
+
+	return result
+}
+
+//marks an optional chain that ran into null, the rest of the chain passes it on without evaluating.
+//it needs its own type, pointers to zero sized values like &object.Null{} can all share one address
+type shortCircuitMarker struct{
+	object.Null
+}
+
+var shortCircuit object.Object = &shortCircuitMarker{}
+
+//evaluates without ending an optional chain, the nodes of a chain evaluate their left side with it
+func (ctx *Context) evalChained(node ast.ASTNode, env *object.Environment) object.Object {
+	if err := ctx.step(); err != nil {
+		return err
+	}
+
+	result := ctx.eval(node, env)
+
+	//the nodes that build a new string, array or hash, builtin results are counted in applyFunction
+	switch node.(type) {
+	case *ast.StringLiteral, *ast.InterpolatedString, *ast.InfixExpression, *ast.ArrayLiteral, *ast.HashLiteral, *ast.SliceExpression:
+		return ctx.allocate(result)
+	}
+
+	return result
+}
+
+func (ctx *Context) eval(node ast.ASTNode, env *object.Environment) object.Object {
 
 	switch node := node.(type) {
 	case *ast.ASTRootNode:
-		return evaluateStatements(node.Statements, env)
+		return ctx.evaluateStatements(node.Statements, env)
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression, env)
+		return ctx.Eval(node.Expression, env)
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 	case *ast.BooleanLiteral:
 		return evaluateBoolean(node.Value)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.InterpolatedString:
+		return ctx.evalInterpolatedString(node, env)
 	case *ast.HashLiteral:
 		
-		return evalHashLiteral(node, env)
+		return ctx.evalHashLiteral(node, env)
 	case *ast.PrefixExpression:
-		right := Eval(node.RightOperator, env)
+		right := ctx.Eval(node.RightOperator, env)
 		if isError(right) {
 			return right
 		}
 		return evaluatePrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
-		left := Eval(node.LeftOperator, env)
+		if node.Operator == "??" {
+			return ctx.evalCoalesce(node, env)
+		}
+
+		left := ctx.Eval(node.LeftOperator, env)
 		if isError(left) {
 			return left
 		}
-		right := Eval(node.RightOperator, env)
+		right := ctx.Eval(node.RightOperator, env)
 
 		if isError(right) {
 			return right
 		}
 		return evaluateInfixExpression(node.Operator, left, right)
 	case *ast.BlockStatement:
-		return evaluateBlockStatements(node.Statements, env)
+		return ctx.evaluateBlockStatements(node.Statements, env)
 	case *ast.IfExpression:
-		return evaluateIfExpression(node, env)
+		return ctx.evaluateIfExpression(node, env)
+	case *ast.TernaryExpression:
+		condition := ctx.Eval(node.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthful(condition) {
+			return ctx.Eval(node.Consequence, env)
+		}
+		return ctx.Eval(node.Alternative, env)
 	case *ast.ReturnStatement:
-		returnVal := Eval(node.ReturnValue, env)
+		returnVal := ctx.Eval(node.ReturnValue, env)
 		if isError(returnVal) {
 			return returnVal
 		}
 		return &object.ReturnValue{Value: returnVal}
 	case *ast.LetStatement:
-		letVal := Eval(node.Value, env)
+		letVal := ctx.Eval(node.Value, env)
 		if isError(letVal) {
 			return letVal
 		}
 		return env.Set(node.Variable.Value, letVal)
 	case *ast.Variable:
-		return evalVariable(node, env)
+		return ctx.evalVariable(node, env)
 	case *ast.FunctionExpression:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Params: params, Body: body, Env: env}
+		if node.Name == "" {
+			return newFunction(node, env)
+		}
+		//a named function expression sees its own name, so it can recurse however it is bound outside
+		fnEnv := object.NewEnclosedEnvironment(env)
+		return fnEnv.Set(node.Name, newFunction(node, fnEnv))
+	case *ast.FunctionStatement:
+		return env.Set(node.Name.Value, newFunction(node.Function, env))
+	case *ast.ImportStatement:
+		return ctx.evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return ctx.Eval(node.Statement, env)
+	case *ast.MemberExpression:
+		return ctx.evalMemberExpression(node, env)
+	case *ast.SpreadExpression:
+		return newError("spread operator not allowed here: %s", node.String())
 	case *ast.CallExpression:
-		fnc := Eval(node.Function, env)
-		if isError(fnc) {
+		if member, ok := node.Function.(*ast.MemberExpression); ok {
+			return ctx.evalMethodCall(member, node.Arguments, env)
+		}
+
+		fnc := ctx.evalChained(node.Function, env)
+		if isError(fnc) || fnc == shortCircuit {
 			return fnc
 		}
-		args := evalArguments(node.Arguments, env)
+		args := ctx.evalArguments(node.Arguments, env)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(fnc, args)
+		return ctx.applyFunction(fnc, args)
 	case *ast.ArrayLiteral:
-		eval := evalArguments(node.Elements, env)
+		eval := ctx.evalArguments(node.Elements, env)
 		if len(eval)==1 && isError(eval[0]){
 			return eval[0]
 		}
 
 		return &object.Array{Elements: eval}
 	case *ast.IndexExpression:
-		left := Eval(node.Left, env)
+		left := ctx.evalChained(node.Left, env)
 		if isError(left){
 			return left
 		}
+		if shortCircuits(left, node.Optional){
+			return shortCircuit
+		}
 
-		index:=Eval(node.Index, env)
+		index:=ctx.Eval(node.Index, env)
 		if isError(index){
 			return index
 		}
 
 		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		return ctx.evalSliceExpression(node, env)
 	default:
 		return NULL
 	}
 
 }
 
-func evaluateStatements(statements []ast.Statement, env *object.Environment) object.Object {
+func (ctx *Context) evaluateStatements(statements []ast.Statement, env *object.Environment) object.Object {
+
+	hoistFunctions(statements, env)
 
 	var result object.Object
 
 	for _, statement := range statements {
-		result = Eval(statement, env)
+		result = ctx.Eval(statement, env)
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
@@ -121,12 +208,14 @@ func evaluateStatements(statements []ast.Statement, env *object.Environment) obj
 	return result
 }
 
-func evaluateBlockStatements(statements []ast.Statement, env *object.Environment) object.Object {
+func (ctx *Context) evaluateBlockStatements(statements []ast.Statement, env *object.Environment) object.Object {
+
+	hoistFunctions(statements, env)
 
 	var result object.Object
 
 	for _, statement := range statements {
-		result = Eval(statement, env)
+		result = ctx.Eval(statement, env)
 
 		if result != nil && (result.Type() == object.RETURN_VAL || result.Type() == object.ERROR_OBJ) {
 			return result
@@ -137,6 +226,23 @@ func evaluateBlockStatements(statements []ast.Statement, env *object.Environment
 	return result
 }
 
+// binds every function declaration of the block before any statement runs,
+// so declarations can call each other regardless of their order
+func hoistFunctions(statements []ast.Statement, env *object.Environment) {
+	for _, statement := range statements {
+		if export, ok := statement.(*ast.ExportStatement); ok {
+			statement = export.Statement
+		}
+		if decl, ok := statement.(*ast.FunctionStatement); ok {
+			env.Set(decl.Name.Value, newFunction(decl.Function, env))
+		}
+	}
+}
+
+func newFunction(node *ast.FunctionExpression, env *object.Environment) *object.Function {
+	return &object.Function{Name: node.Name, BoundName: node.BoundName, Params: node.Parameters, Defaults: node.Defaults, Rest: node.Rest, Body: node.Body, Env: env}
+}
+
 func evaluateBoolean(val bool) object.Object {
 	if val {
 		return TRUE
@@ -186,9 +292,9 @@ func evaluateInfixExpression(operator string, left, right object.Object) object.
 	case left.Type() == object.STRING_VAL && right.Type() == object.STRING_VAL:
 		return evaluateStringInfixExpression(operator, left, right)
 	case operator == "==":
-		return evaluateBoolean(left == right)
+		return evaluateBoolean(object.Equal(left, right))
 	case operator == "!=":
-		return evaluateBoolean(left != right)
+		return evaluateBoolean(!object.Equal(left, right))
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	default:
@@ -208,6 +314,9 @@ func evaluateIntegerInfixExpression(operator string, left, right object.Object)
 	case "*":
 		return &object.Integer{Value: lval * rval}
 	case "/":
+		if rval == 0 {
+			return newError("division by zero")
+		}
 		return &object.Integer{Value: lval / rval}
 	case ">":
 		return evaluateBoolean(lval > rval)
@@ -243,38 +352,67 @@ func evaluateStringInfixExpression(operator string, left, right object.Object) o
 }
 
 
-func evaluateIfExpression(node *ast.IfExpression, env *object.Environment) object.Object {
-	condition := Eval(node.Condition, env)
+func (ctx *Context) evaluateIfExpression(node *ast.IfExpression, env *object.Environment) object.Object {
+	condition := ctx.Eval(node.Condition, env)
 	if isError(condition) {
 		return condition
 	}
 	if isTruthful(condition) {
-		return Eval(node.Consequence, env)
+		return ctx.Eval(node.Consequence, env)
 	} else if node.Alternative != nil {
-		return Eval(node.Alternative, env)
+		return ctx.Eval(node.Alternative, env)
 	} else {
 		return NULL
 	}
 
 }
 
-func evalVariable(node *ast.Variable, env *object.Environment) object.Object {
+func (ctx *Context) evalInterpolatedString(node *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range node.Parts {
+		val := ctx.Eval(part, env)
+		if isError(val) {
+			return val
+		}
+
+		out.WriteString(stringify(val))
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+func (ctx *Context) evalVariable(node *ast.Variable, env *object.Environment) object.Object {
 	if val, ok := env.Get(node.Value); ok{
 		return val
 	}
 
-	if builtin, ok := builtins[node.Value]; ok{
+	if builtin, ok := ctx.Builtins[node.Value]; ok{
 		return builtin
 	}
 
+	if err := disabledBuiltinError(node.Value); err != nil{
+		return err
+	}
+
 	return newError("variable not found: %s", node.Value)
 }
 
-func evalArguments(args []ast.Expression, env *object.Environment) []object.Object {
+func (ctx *Context) evalArguments(args []ast.Expression, env *object.Environment) []object.Object {
 	var result []object.Object
 
 	for _, e := range args {
-		eval := Eval(e, env)
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			elements := ctx.evalSpread(spread, env)
+			if len(elements) == 1 && isError(elements[0]) {
+				return elements
+			}
+
+			result = append(result, elements...)
+			continue
+		}
+
+		eval := ctx.Eval(e, env)
 		if isError(eval) {
 			return []object.Object{eval}
 		}
@@ -285,10 +423,26 @@ func evalArguments(args []ast.Expression, env *object.Environment) []object.Obje
 	return result
 }
 
+func (ctx *Context) evalSpread(spread *ast.SpreadExpression, env *object.Environment) []object.Object {
+	eval := ctx.Eval(spread.Value, env)
+	if isError(eval) {
+		return []object.Object{eval}
+	}
+
+	arr, ok := eval.(*object.Array)
+	if !ok {
+		return []object.Object{newError("spread operator expects an array, got=%s", eval.Type())}
+	}
+
+	return arr.Elements
+}
+
 func evalIndexExpression(left, index object.Object) object.Object{
 	switch{
 	case left.Type()==object.ARRAY_OBJ && index.Type()==object.INTEGER_VAL:
 		return evalArrayIndexExpression(left, index)
+	case left.Type()==object.STRING_VAL && index.Type()==object.INTEGER_VAL:
+		return evalStringIndexExpression(left, index)
 	case left.Type()==object.HASHPAIR_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
@@ -298,82 +452,276 @@ func evalIndexExpression(left, index object.Object) object.Object{
 
 func evalHashIndexExpression(left, index object.Object) object.Object{
 	hashMap := left.(*object.Hash)
-	key, ok := index.(object.Hashable)
-	if !ok{
+	if _, ok := object.HashKeyOf(index); !ok{
 		return newError("the key is not usable as hashkey , got=%s", index.Type())
 	}
 
-	pair, ok := hashMap.Pairs[key.HashKey()]
+	value, ok := hashMap.Get(index)
 	if !ok{
 		return NULL
 	}
 
-	return pair.Value
+	return value
+}
+
+//`receiver.name(args)` calls a module export or a function stored in the hash under name,
+//anything else calls the builtin name with the receiver as its first argument, e.g. `s.upper()` is `upper(s)`
+func (ctx *Context) evalMethodCall(member *ast.MemberExpression, arguments []ast.Expression, env *object.Environment) object.Object{
+	receiver := ctx.evalChained(member.Left, env)
+	if isError(receiver){
+		return receiver
+	}
+	if shortCircuits(receiver, member.Optional){
+		return shortCircuit
+	}
+
+	args := ctx.evalArguments(arguments, env)
+	if len(args) == 1 && isError(args[0]){
+		return args[0]
+	}
+
+	name := member.Property.Value
+	switch receiver := receiver.(type){
+	case *object.Module:
+		fn := moduleExport(receiver, name)
+		if isError(fn){
+			return fn
+		}
+		return ctx.applyFunction(fn, args)
+	case *object.Hash:
+		if fn, ok := receiver.Get(&object.String{Value: name}); ok{
+			return ctx.applyFunction(fn, args)
+		}
+	}
+
+	builtin, ok := ctx.Builtins[name]
+	if !ok{
+		if err := disabledBuiltinError(name); err != nil{
+			return err
+		}
+		return newError("no method %s for %s", name, receiver.Type())
+	}
+
+	return ctx.applyFunction(builtin, append([]object.Object{receiver}, args...))
+}
+
+//true when the link is skipped, either an earlier optional link hit null or this one does
+func shortCircuits(left object.Object, optional bool) bool{
+	return left == shortCircuit || (optional && left == NULL)
+}
+
+//`left ?? right` only evaluates right when left is null
+func (ctx *Context) evalCoalesce(node *ast.InfixExpression, env *object.Environment) object.Object{
+	left := ctx.Eval(node.LeftOperator, env)
+	if left != NULL{
+		return left
+	}
+
+	return ctx.Eval(node.RightOperator, env)
 }
 
+//negative indexes count from the end, -1 is the last element
 func evalArrayIndexExpression(left, index object.Object) object.Object{
 	arr := left.(*object.Array)
 	ind := index.(*object.Integer).Value
 
 	max := int64(len(arr.Elements))
-	if ind<0 || ind>=max{
-		return newError("array out of bound index, min index=%d, max index=%d, got=%d",0,max-1, ind)
+	if ind< -max || ind>=max{
+		return newError("array out of bound index, min index=%d, max index=%d, got=%d",-max,max-1, ind)
+	}
+
+	if ind < 0{
+		ind += max
 	}
 
 	return arr.Elements[ind]
 }
 
-func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object{
+func evalStringIndexExpression(left, index object.Object) object.Object{
+	runes := []rune(left.(*object.String).Value)
+	ind := index.(*object.Integer).Value
+
+	max := int64(len(runes))
+	if ind< -max || ind>=max{
+		return newError("string out of bound index, min index=%d, max index=%d, got=%d",-max,max-1, ind)
+	}
+
+	if ind < 0{
+		ind += max
+	}
+
+	return &object.String{Value: string(runes[ind])}
+}
+
+func (ctx *Context) evalSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object{
+	left := ctx.evalChained(node.Left, env)
+	if isError(left){
+		return left
+	}
+	if shortCircuits(left, node.Optional){
+		return shortCircuit
+	}
+
+	var length int64
+	switch left := left.(type){
+	case *object.Array:
+		length = int64(len(left.Elements))
+	case *object.String:
+		length = int64(utf8.RuneCountInString(left.Value))
+	default:
+		return newError("slice operator not supported, got=%s", left.Type())
+	}
+
+	start, err := ctx.evalSliceBound(node.Start, env, 0, length)
+	if err != nil{
+		return err
+	}
+
+	end, err := ctx.evalSliceBound(node.End, env, length, length)
+	if err != nil{
+		return err
+	}
+
+	if end < start{
+		end = start
+	}
+
+	switch left := left.(type){
+	case *object.Array:
+		elements := make([]object.Object, end-start)
+		copy(elements, left.Elements[start:end])
+		return &object.Array{Elements: elements}
+	default:
+		return &object.String{Value: string([]rune(left.(*object.String).Value)[start:end])}
+	}
+}
+
+//like python, a negative bound counts from the end and bounds past either end are clamped
+func (ctx *Context) evalSliceBound(bound ast.Expression, env *object.Environment, missing, length int64) (int64, object.Object){
+	if bound == nil{
+		return missing, nil
+	}
+
+	eval := ctx.Eval(bound, env)
+	if isError(eval){
+		return 0, eval
+	}
+
+	integer, ok := eval.(*object.Integer)
+	if !ok{
+		return 0, newError("slice bounds have to be integers, got=%s", eval.Type())
+	}
+
+	return clampSliceBound(integer.Value, length), nil
+}
+
+func clampSliceBound(ind, length int64) int64{
+	if ind < 0{
+		ind += length
+	}
+
+	if ind < 0{
+		return 0
+	}
+	if ind > length{
+		return length
+	}
+
+	return ind
+}
+
+func (ctx *Context) evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object{
 	
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for key, value := range node.Pairs{
-		keyEval := Eval(key, env)
+	for _, pair := range node.Pairs{
+		keyEval := ctx.Eval(pair.Key, env)
 		if isError(keyEval){
 			return keyEval
 		}
 
-		hashKey, ishashable := keyEval.(object.Hashable)
-		if !ishashable{
-			return newError("unsuable as a hash map key, expected=integer, string or boolean , got=%s", keyEval.Type())
+		if _, ishashable := object.HashKeyOf(keyEval); !ishashable{
+			return newError("unsuable as a hash map key, expected=integer, string, boolean or array of those , got=%s", keyEval.Type())
 		}
 
-		valueEval := Eval(value, env)
+		valueEval := ctx.Eval(pair.Value, env)
 		if isError(valueEval){
 			return valueEval
 		}
 
-		hashedKey := hashKey.HashKey()
-		pairs[hashedKey]=object.HashPair{Key: keyEval, Value: valueEval}
+		hash.Set(keyEval, valueEval)
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
-func applyFunction(fnc object.Object, args []object.Object) object.Object {
-	
+func (ctx *Context) applyFunction(fnc object.Object, args []object.Object) object.Object {
+	if err := ctx.checkCancelled(); err != nil {
+		return err
+	}
+
 	switch fn := fnc.(type){
 	case *object.Function:
-		fnEnv := newFunctionEnvironment(fn, args)
-		eval := Eval(fn.Body, fnEnv)
-		return unwrap(eval)
+		if ctx.depth >= ctx.MaxDepth {
+			return newError("maximum call depth of %d exceeded", ctx.MaxDepth)
+		}
+		ctx.depth++
+		defer func() { ctx.depth-- }()
+
+		fnEnv, err := ctx.newFunctionEnvironment(fn, args)
+		if err != nil {
+			return addTraceFrame(err, fn)
+		}
+		eval := unwrap(ctx.Eval(fn.Body, fnEnv))
+		if err, ok := eval.(*object.Error); ok {
+			return addTraceFrame(err, fn)
+		}
+		return eval
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return ctx.allocateResult(fn.Fn(ctx, args...), args)
 	default:
 		return newError("not a function: %s", fnc.Type())
 	}
 
 }
 
-func newFunctionEnvironment(fn *object.Function, args []object.Object) *object.Environment {
+func (ctx *Context) newFunctionEnvironment(fn *object.Function, args []object.Object) (*object.Environment, *object.Error) {
 
 	extendedEnv := object.NewEnclosedEnvironment(fn.Env)
 
-	for argIdx, arg := range fn.Params {
-		extendedEnv.Set(arg.Value, args[argIdx])
+	for argIdx, param := range fn.Params {
+		if argIdx < len(args) {
+			extendedEnv.Set(param.Value, args[argIdx])
+			continue
+		}
+
+		def, ok := fn.Defaults[param.Value]
+		if !ok {
+			return nil, newError("wrong number of args, expected a value for %s, got=%d", param.Value, len(args))
+		}
+
+		// defaults run inside the call so they can see the parameters bound before them
+		val := ctx.Eval(def, extendedEnv)
+		if isError(val) {
+			return nil, val.(*object.Error)
+		}
+		extendedEnv.Set(param.Value, val)
+	}
+
+	if fn.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fn.Params) {
+			rest = append(rest, args[len(fn.Params):]...)
+		}
+		extendedEnv.Set(fn.Rest.Value, &object.Array{Elements: rest})
 	}
 
-	return extendedEnv
+	return extendedEnv, nil
+}
+
+func addTraceFrame(err *object.Error, fn *object.Function) *object.Error {
+	err.AddFrame(fn.DisplayName())
+	return err
 }
 
 func unwrap(obj object.Object) object.Object {
@@ -1,6 +1,11 @@
 package evaluation
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/singlaanish56/Interpreter-In-Go/lexer"
@@ -145,6 +150,10 @@ func TestErrorHandling(t *testing.T){
 			"5 + true",
 			"type mismatch: INTEGER + BOOLEAN",
 		},
+		{
+			"1 / 0",
+			"division by zero",
+		},
 		{
 			"-true",
 			"unknown operator: -BOOLEAN",
@@ -169,8 +178,11 @@ func TestErrorHandling(t *testing.T){
 			`"hello" - "worls"`,
 			"unknown operator: STRING - STRING",
 		},
-		{"[1,2,3][3]","array out of bound index, min index=0, max index=2, got=3"},
-		{"[1,2,3][-1]","array out of bound index, min index=0, max index=2, got=-1"},
+		{"[1,2,3][3]","array out of bound index, min index=-3, max index=2, got=3"},
+		{"[1,2,3][-4]","array out of bound index, min index=-3, max index=2, got=-4"},
+		{`"abc"[3]`,"string out of bound index, min index=-3, max index=2, got=3"},
+		{`"abc"[1:"x"]`,"slice bounds have to be integers, got=STRING"},
+		{`5[1:2]`,"slice operator not supported, got=INTEGER"},
 		{`{"name":"Monkey"}[fn(x){x}];`,"the key is not usable as hashkey , got=FUNCTION"},
 	}
 	for _,tt := range tests{
@@ -251,6 +263,148 @@ func TestEvalFunctionApplication(t *testing.T){
 	}
 }
 
+func TestEvalDefaultAndRestParameters(t *testing.T){
+	tests := []struct{
+		input string
+		expected interface{}
+	}{
+		{"let add = fn(a, b = 10){a+b;}; add(5);", 15},
+		{"let add = fn(a, b = 10){a+b;}; add(5, 1);", 6},
+		{"let f = fn(a, b = a * 2){a+b;}; f(3);", 9},
+		{"let n = 1; let f = fn(a = n){a;}; let n = 7; f();", 7},
+		{"let f = fn(a, ...rest){len(rest);}; f(1, 2, 3);", 2},
+		{"let f = fn(a, ...rest){len(rest);}; f(1);", 0},
+		{"let f = fn(...args){last(args);}; f(1, 2, 3);", 3},
+		{"let add = fn(a, b){a+b;}; add(5);", "wrong number of args, expected a value for b, got=1"},
+		{"let f = fn(a = foo){a;}; f();", "variable not found: foo"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+
+		switch expected := tt.expected.(type){
+		case int:
+			testIntegerObject(t, eval, int64(expected))
+		case string:
+			testErrorObject(t, eval, expected)
+		}
+	}
+}
+
+func TestEvalSpreadArguments(t *testing.T){
+	tests := []struct{
+		input string
+		expected interface{}
+	}{
+		{"let add = fn(a, b, c){a+b+c;}; let arr = [1, 2, 3]; add(...arr);", 6},
+		{"let add = fn(a, b, c){a+b+c;}; add(1, ...[2, 3]);", 6},
+		{"let f = fn(...args){len(args);}; f(...[1, 2], ...[3, 4]);", 4},
+		{"let arr = [2, 3]; len([1, ...arr, 4]);", 4},
+		{"let arr = [2, 3]; [1, ...arr, 4][2];", 3},
+		{"len([...[]]);", 0},
+		{"let f = fn(a){a;}; f(...5);", "spread operator expects an array, got=INTEGER"},
+		{"...[1, 2]", "spread operator not allowed here: ...[1,2]"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+
+		switch expected := tt.expected.(type){
+		case int:
+			testIntegerObject(t, eval, int64(expected))
+		case string:
+			testErrorObject(t, eval, expected)
+		}
+	}
+}
+
+func TestEvalNamedFunctions(t *testing.T){
+	tests := []struct{
+		input string
+		expected int64
+	}{
+		{"fn double(x){x*2;} double(4);", 8},
+		{"let r = double(4); fn double(x){x*2;} r;", 8},
+		{`fn isEven(n){if(n==0){return 1;} isOdd(n-1);}
+		  fn isOdd(n){if(n==0){return 0;} isEven(n-1);}
+		  isEven(10);`, 1},
+		{"let f = fn(){ let r = inner(); fn inner(){5;} r; }; f();", 5},
+		{"fn fact(n){if(n<2){return 1;} n*fact(n-1);} fact(5);", 120},
+		{"let f = fn g(n){ if (n == 0) { 0 } else { g(n - 1) } }; f(3);", 0},
+		{"let g = 7; let f = fn g(){ 1 }; g;", 7},
+		{"map([3], fn fact(n){ if (n < 2) { 1 } else { n * fact(n - 1) } })[0];", 6},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		testIntegerObject(t, eval, tt.expected)
+	}
+}
+
+func TestEvalFunctionInspect(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn add(a, b = 1){a+b;}", "fn add(a,b=1){\n(a+b)}\n"},
+		{"let sub = fn(a, b){a-b;}; sub;", "fn(a,b){\n(a-b)}\n"},
+		{"fn(...args){args;}", "fn(...args){\nargs}\n"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		fn, ok := eval.(*object.Function)
+		if !ok{
+			t.Errorf("object is not function, got=%T", eval)
+			continue
+		}
+
+		if fn.Inspect() != tt.expected{
+			t.Errorf("function inspect not as expected=%q, got=%q", tt.expected, fn.Inspect())
+		}
+	}
+}
+
+func TestEvalErrorTrace(t *testing.T){
+	input := `fn inner(){ missing; }
+	fn outer(){ inner(); }
+	let wrapper = fn(){ outer(); };
+	fn(){ wrapper(); }();`
+
+	eval := testEval(input)
+	errObj, ok := eval.(*object.Error)
+	if !ok{
+		t.Fatalf("no error object returned, got=%T", eval)
+	}
+
+	expected := []string{"inner", "outer", "wrapper", "<anonymous>"}
+	if len(errObj.Trace) != len(expected){
+		t.Fatalf("the trace length not as expected=%d, got=%v", len(expected), errObj.Trace)
+	}
+
+	for i, frame := range expected{
+		if errObj.Trace[i] != frame{
+			t.Errorf("trace[%d] not as expected=%s, got=%s", i, frame, errObj.Trace[i])
+		}
+	}
+
+	if errObj.Inspect() != "variable not found: missing\n\tat inner\n\tat outer\n\tat wrapper\n\tat <anonymous>"{
+		t.Errorf("error inspect not as expected, got=%q", errObj.Inspect())
+	}
+
+	//recursion collapses into one line and a deep mix of functions is cut off
+	eval = testEval(`fn f(n){ if (n == 0) { missing } else { f(n - 1) } } f(500)`)
+	if eval.Inspect() != "variable not found: missing\n\tat f (x501)"{
+		t.Errorf("error inspect not as expected, got=%q", eval.Inspect())
+	}
+
+	eval = testEval(`fn f(n){ if (n == 0) { missing } else { g(n - 1) } } fn g(n){ f(n) } f(200)`)
+	trace := eval.(*object.Error).Trace
+	if len(trace) != 65 || trace[0] != "f" || trace[1] != "g" || trace[64] != "... 337 more"{
+		t.Errorf("trace not cut off as expected, got %d frames ending in %q", len(trace), trace[len(trace)-1])
+	}
+}
+
 func TestEvalBuiltInFunction(t *testing.T){
 	tests := []struct{
 		input string
@@ -289,6 +443,328 @@ func TestEvalBuiltInFunction(t *testing.T){
 	}
 }
 
+func TestEvalHigherOrderBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"map([1,2,3], fn(x){x*2})", "[2,4,6]"},
+		{"map([], fn(x){x*2})", "[]"},
+		{`map([[1],[1,2]], len)`, "[1,2]"},
+		{"filter([1,2,3,4], fn(x){x > 2})", "[3,4]"},
+		{"reduce([1,2,3,4], fn(acc, x){acc + x})", "10"},
+		{"reduce([1,2,3], fn(acc, x){acc * x}, 10)", "60"},
+		{"reduce([], fn(acc, x){acc + x}, 0)", "0"},
+		{"sort([3,1,2])", "[1,2,3]"},
+		{`sort(["b","c","a"])`, "[a,b,c]"},
+		{"sort([3,1,2], fn(a, b){a > b})", "[3,2,1]"},
+		{"sort([3,1,2], fn(a, b){a - b})", "[1,2,3]"},
+		{"let arr = [3,1,2]; sort(arr); arr", "[3,1,2]"},
+		{`zip([1,2,3], ["a","b"])`, "[[1,a],[2,b]]"},
+		{"range(4)", "[0,1,2,3]"},
+		{"range(2, 5)", "[2,3,4]"},
+		{"range(5, 0, -2)", "[5,3,1]"},
+		{"range(0)", "[]"},
+		{"range(-3)", "[]"},
+		{"range(9223372036854775806, 9223372036854775807, 4611686018427387904)", "[9223372036854775806]"},
+		{"range(9, -9223372036854775807 - 1, -9223372036854775807)", "[9,-9223372036854775798]"},
+		{"any([1,2,3], fn(x){x > 2})", "true"},
+		{"any([1,2,3], fn(x){x > 3})", "false"},
+		{"all([1,2,3], fn(x){x > 0})", "true"},
+		{"all([1,2,3], fn(x){x > 1})", "false"},
+		{"any([false, false])", "false"},
+		{"all([])", "true"},
+		{"let double = fn(x){x*2}; reduce(map(filter(range(10), fn(x){x > 6}), double), fn(a, b){a + b})", "48"},
+		{"map(1, fn(x){x})", "argument for the map builtin not supported, got INTEGER"},
+		{"filter([1], 2)", "argument for the filter builtin not supported, expected a function, got INTEGER"},
+		{"reduce([], fn(a, b){a})", "reduce of an empty array needs an initial value"},
+		{`sort([1, "a"])`, "sort without a comparator needs all integers or all strings, got STRING"},
+		{`sort([1, 2], fn(a, b){"x"})`, "sort comparator has to return a boolean or an integer, got STRING"},
+		{"range(1, 5, 0)", "range step cannot be zero"},
+		{"range(0, 9223372036854775807, 4611686018427387904)", "[0,4611686018427387904]"},
+		{"range(0, 9223372036854775807)", "range of 9223372036854775807 elements exceeds the limit of 16777216"},
+		{"map([1], fn(x){x + true})", "type mismatch: INTEGER + BOOLEAN\n\tat <anonymous>"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalStringBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`split("a,b,c", ",")`, "[a,b,c]"},
+		{`split("  a b   c ")`, "[a,b,c]"},
+		{`split("abc", "")`, "[a,b,c]"},
+		{`join(["a","b","c"], "-")`, "a-b-c"},
+		{`join(["a","b"])`, "ab"},
+		{`trim("  hi  ")`, "hi"},
+		{`trim("xxhixx", "x")`, "hi"},
+		{`upper("héllo")`, "HÉLLO"},
+		{`lower("HeLLo")`, "hello"},
+		{`contains("monkey business", "key")`, "true"},
+		{`contains("monkey", "donkey")`, "false"},
+		{`starts_with("monkey", "mon")`, "true"},
+		{`ends_with("monkey", "mon")`, "false"},
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`replace("a-b-c", "-", "+", 1)`, "a+b-c"},
+		{`index_of("héllo", "l")`, "2"},
+		{`index_of("hello", "z")`, "-1"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`chars("héy")`, "[h,é,y]"},
+		{`substr("héllo", 1, 3)`, "éll"},
+		{`substr("héllo", 2)`, "llo"},
+		{`len("héllo")`, "5"},
+		{`upper(1)`, "argument for the upper builtin not supported, got INTEGER"},
+		{`join([1, 2], ",")`, "join expects an array of strings, got INTEGER at index 0"},
+		{`repeat("a", -1)`, "repeat count cannot be negative, got=-1"},
+		{`repeat("ab", 9000000000000000000)`, "repeat result of 9000000000000000000 x 2 bytes exceeds the limit of 16777216"},
+		{`len(repeat("", 9000000000000000000))`, "0"},
+		{`substr("abc", 2, 5)`, "substr out of bound, string length=3, got start=2, length=5"},
+		{`substr("abc", 1, 9223372036854775807)`, "substr out of bound, string length=3, got start=1, length=9223372036854775807"},
+		{`contains("abc")`, "wrong number of args, expected=2, got=1"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalHashBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`keys({"b":1, "a":2, 3:3})`, "[b,a,3]"},
+		{`values({"b":1, "a":2})`, "[1,2]"},
+		{`entries({"b":1, "a":2})`, "[[b,1],[a,2]]"},
+		{`keys({})`, "[]"},
+		{`has({"a":1}, "a")`, "true"},
+		{`has({"a":1}, "b")`, "false"},
+		{`has({[1,2]:1}, [1,2])`, "true"},
+		{`delete({"a":1, "b":2, "c":3}, "b")`, "{a:1,c:3}"},
+		{`delete({"a":1, "b":2, "c":3}, "a", "c", "x")`, "{b:2}"},
+		{`let h = {"a":1, "b":2}; delete(h, "a"); h`, "{a:1,b:2}"},
+		{`merge({"a":1, "b":2}, {"b":3, "c":4})`, "{a:1,b:3,c:4}"},
+		{`let h = {"a":1}; merge(h, {"b":2}); h`, "{a:1}"},
+		{`merge({"a":1})`, "{a:1}"},
+		{`len({"a":1, "b":2})`, "2"},
+		{`len({})`, "0"},
+		{`keys([1])`, "argument for the keys builtin not supported, got ARRAY"},
+		{`has({}, fn(x){x})`, "the key is not usable as hashkey , got=FUNCTION"},
+		{`merge({}, 1)`, "argument for the merge builtin not supported, got INTEGER"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalArrayBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"push_back([], 1)", "[1]"},
+		{"push_back([1, 2], 3)", "[1,2,3]"},
+		{"let arr = [1]; push_back(arr, 2); arr", "[1]"},
+		{"rest([])", "[]"},
+		{"rest([1, 2, 3])", "[2,3]"},
+		{"let build = fn(n, acc){ if(n == 0){ return acc; } build(n-1, push_back(acc, n)); }; build(3, [])", "[3,2,1]"},
+		{"pop([1, 2, 3])", "[1,2]"},
+		{"pop([])", "[]"},
+		{"let arr = [1, 2]; pop(arr); arr", "[1,2]"},
+		{"insert([1, 3], 1, 2)", "[1,2,3]"},
+		{"insert([1, 2], 2, 3)", "[1,2,3]"},
+		{"insert([2, 3], 0, 1)", "[1,2,3]"},
+		{"insert([1, 3], -1, 2)", "[1,2,3]"},
+		{"concat([1], [2, 3], [])", "[1,2,3]"},
+		{"concat()", "[]"},
+		{"reverse([1, 2, 3])", "[3,2,1]"},
+		{"slice([1, 2, 3, 4], 1, 3)", "[2,3]"},
+		{"slice([1, 2, 3, 4], -2)", "[3,4]"},
+		{"index_of([1, [2], 3], [2])", "1"},
+		{"index_of([1, 2], 5)", "-1"},
+		{`contains([1, "a", true], "a")`, "true"},
+		{"contains([1, 2], 3)", "false"},
+		{"flatten([1, [2, [3]], [4]])", "[1,2,[3],4]"},
+		{"flatten([1, [2, [3, [4]]]], 10)", "[1,2,3,4]"},
+		{`unique([1, 2, 1, "a", "a", [1], [1]])`, "[1,2,a,[1]]"},
+		{"sum([1, 2, 3])", "6"},
+		{"sum([])", "0"},
+		{"pop(1)", "argument for the pop builtin not supported, got INTEGER"},
+		{`sum([1, "a"])`, "sum expects an array of integers, got STRING at index 1"},
+		{"concat([1], 2)", "argument for the concat builtin not supported, got INTEGER"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalMathBuiltins(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"abs(-5)", "5"},
+		{"abs(5)", "5"},
+		{"min(3, 1, 2)", "1"},
+		{"max(3, 1, 2)", "3"},
+		{"min([4, -2, 7])", "-2"},
+		{"max([4])", "4"},
+		{"pow(2, 10)", "1024"},
+		{"pow(-3, 3)", "-27"},
+		{"pow(5, 0)", "1"},
+		{"pow(2, 62)", "4611686018427387904"},
+		{"sqrt(16)", "4"},
+		{"sqrt(17)", "4"},
+		{"sqrt(0)", "0"},
+		{"sqrt(1)", "1"},
+		{"sqrt(9223372030926249001)", "3037000499"},
+		{"sqrt(9223372030926249000)", "3037000498"},
+		{"sqrt(9223372036854775806)", "3037000499"},
+		{"sqrt(9223372036854775807)", "3037000499"},
+		{"floor(7)", "7"},
+		{"ceil(-7)", "-7"},
+		{"round(3)", "3"},
+		{"clamp(5, 0, 10)", "5"},
+		{"clamp(-5, 0, 10)", "0"},
+		{"clamp(15, 0, 10)", "10"},
+		{"sqrt(-1)", "sqrt of a negative number, got -1"},
+		{"pow(2, 63)", "integer overflow in pow(2, 63)"},
+		{"pow(10, 100)", "integer overflow in pow(10, 100)"},
+		{"pow(2, -1)", "negative exponent not supported for integers, got pow(2, -1)"},
+		{"min([])", "min needs at least one value"},
+		{`max(1, "a")`, "argument for the max builtin not supported, got STRING"},
+		{"clamp(1, 10, 0)", "clamp lower bound is greater than the upper bound, got low=10, high=0"},
+		{`abs("a")`, "argument for the abs builtin not supported, got STRING"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalJSONParse(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`{"b": 1, "a": [true, null, "x"]}`, "{b:1,a:[true,null,x]}"},
+		{`[1, -2, 3]`, "[1,-2,3]"},
+		{`"str"`, "str"},
+		{`null`, "null"},
+		{`{"a": {"b": {}}}`, "{a:{b:{}}}"},
+		{`1.5`, "invalid json: number 1.5 is not an integer"},
+		{`[1,`, "invalid json: unexpected end of JSON input"},
+		{`1 2`, "invalid json: unexpected data after the top level value"},
+	}
+
+	parse := builtins["json_parse"]
+	for _, tt := range tests{
+		eval := parse.Fn(NewContext(nil, nil), &object.String{Value: tt.input})
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+
+	hash, ok := parse.Fn(NewContext(nil, nil), &object.String{Value: `{"n": 1, "ok": false}`}).(*object.Hash)
+	if !ok{
+		t.Fatalf("json object should become a hash")
+	}
+
+	value, _ := hash.Get(&object.String{Value: "ok"})
+	testBooleanObject(t, value, false)
+}
+
+func TestEvalJSONStringify(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`json_stringify({"b": 1, "a": [true, "x"]})`, `{"b":1,"a":[true,"x"]}`},
+		{`json_stringify([])`, "[]"},
+		{`json_stringify({})`, "{}"},
+		{`json_stringify("a<b>")`, `"a<b>"`},
+		{`json_stringify(if(false){1})`, "null"},
+		{`json_stringify({"a": [1, 2]}, 2)`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+		{`json_stringify([1], "--")`, "[\n--1\n]"},
+		{`let h = {"z": 1, "y": {"x": [2]}}; json_parse(json_stringify(h)) == h`, "true"},
+		{`json_stringify({1: "a"})`, "json object keys have to be strings, got INTEGER"},
+		{`json_stringify([fn(x){x}])`, "cannot convert FUNCTION to json"},
+		{`json_stringify(len)`, "cannot convert BUILTIN to json"},
+		{`json_stringify(1, -1)`, "json indent cannot be negative, got=-1"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalFormatAndInterpolation(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let name = "monkey"; "Hello ${name}!"`, "Hello monkey!"},
+		{`let a = 2; "${a} * ${a} = ${a * a}"`, "2 * 2 = 4"},
+		{`let h = {"k": [1, 2]}; "value: ${h["k"]}"`, "value: [1,2]"},
+		{`"nested ${ "inner ${1 + 1}" }"`, "nested inner 2"},
+		{`"${true}${if(false){1}}"`, "truenull"},
+		{`"${missing}"`, "variable not found: missing"},
+		{`str(42)`, "42"},
+		{`str("a") + str([1, "b"])`, "a[1,b]"},
+		{`"n=" + str(-5)`, "n=-5"},
+		{`format("{} is {}", "x", 1)`, "x is 1"},
+		{`format("{1}-{0}-{1}", "a", "b")`, "b-a-b"},
+		{`format("[{:>5}]", "ab")`, "[   ab]"},
+		{`format("[{:<5}]", 12)`, "[12   ]"},
+		{`format("[{:5}]", 12)`, "[   12]"},
+		{`format("[{:5}]", "ab")`, "[ab   ]"},
+		{`format("[{:*^6}]", "ab")`, "[**ab**]"},
+		{`format("[{:03}]", 7)`, "[007]"},
+		{`format("{:05}", -42)`, "-0042"},
+		{`format("{:02}", -42)`, "-42"},
+		{`format("{:*>5}", -42)`, "**-42"},
+		{`format("{{}} {}", 1)`, "{} 1"},
+		{`format("no placeholders")`, "no placeholders"},
+		{`format("{} {}", 1)`, "format placeholder {} has no matching argument, got 1 args"},
+		{`format("{:x}", 1)`, "invalid format spec \"x\""},
+		{`format("{", 1)`, "format has an unclosed { at position 0"},
+		{`format("a } b")`, "format has a single } at position 2, use }} for a literal brace"},
+		{`format(1)`, "argument for the format builtin not supported, got INTEGER"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
 func TestEvalArrayLiteral(t * testing.T){
 	input :="[1+2, 2, 3*3, 4-4]"
 
@@ -318,6 +794,8 @@ func TestEvalArrayIndex(t *testing.T){
 		{"[1,2,3][1]", 2},
 		{"let arr = [1,2,3]; arr[2];", 3},
 		{"let arr = [1,2,3]; arr[0]+arr[1]+arr[2];", 6},
+		{"[1,2,3][-1]", 3},
+		{"[1,2,3][-3]", 1},
 	}
 
 	for _, tt := range tests{
@@ -330,6 +808,36 @@ func TestEvalArrayIndex(t *testing.T){
 	}
 }
 
+func TestEvalStringIndexAndSlices(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`"abc"[0]`, "a"},
+		{`"héllo"[1]`, "é"},
+		{`"abc"[-1]`, "c"},
+		{`"héllo"[1:3]`, "él"},
+		{`"hello"[:2]`, "he"},
+		{`"hello"[-2:]`, "lo"},
+		{`"hello"[:]`, "hello"},
+		{`"hello"[3:1]`, ""},
+		{`"hello"[1:100]`, "ello"},
+		{"[1,2,3,4][1:3]", "[2,3]"},
+		{"[1,2,3,4][:-1]", "[1,2,3]"},
+		{"[1,2,3,4][-2:]", "[3,4]"},
+		{"[1,2,3,4][-10:2]", "[1,2]"},
+		{"let n = 2; [1,2,3,4][n:n+1]", "[3]"},
+		{"let arr = [1,2,3]; let s = arr[:]; s == arr", "true"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("result for %q not as expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
 func TestEvalHashIndex(t *testing.T){
 	tests:=[]struct{
 		input string
@@ -372,32 +880,421 @@ func TestHashLiterals(t *testing.T){
 		t.Fatalf("the type of eval is not as expected, got=%T", eval)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value:"one"}).HashKey() : 1,
-		(&object.String{Value:"two"}).HashKey() : 2,
-		(&object.String{Value:"three"}).HashKey() : 3,
-		(&object.Integer{Value:4}).HashKey() : 4,
-		(&object.Boolean{Value:true}).HashKey() : 5,
-		(&object.Boolean{Value:false}).HashKey() : 6,
+	expected := []struct{
+		key object.Object
+		value int64
+	}{
+		{&object.String{Value:"one"}, 1},
+		{&object.String{Value:"two"}, 2},
+		{&object.String{Value:"three"}, 3},
+		{&object.Integer{Value:4}, 4},
+		{&object.Boolean{Value:true}, 5},
+		{&object.Boolean{Value:false}, 6},
+	}
+
+	if result.Len() != len(expected){
+		t.Errorf("the length of the map not as expected=%d, got=%d", len(expected), result.Len())
 	}
 
-	if len(result.Pairs) != len(expected){
-		t.Errorf("the length of the map not as expected=%d, got=%d", len(expected), len(result.Pairs))
+	for i, pair := range result.Pairs(){
+		if pair.Key.Inspect() != expected[i].key.Inspect(){
+			t.Errorf("the pair %d is out of order, expected key=%s, got=%s", i, expected[i].key.Inspect(), pair.Key.Inspect())
+		}
 	}
 
-	for expectedKey, expectedValue := range expected{
-		pairs, ok := result.Pairs[expectedKey]
+	for _, exp := range expected{
+		value, ok := result.Get(exp.key)
 		if !ok{
-			t.Errorf("the mapping value not found, expected=%T", expectedKey)
+			t.Errorf("the mapping value not found, expected=%s", exp.key.Inspect())
+			continue
+		}
+
+		testIntegerObject(t, value, exp.value)
+	}
+}
+
+func TestHashInspectOrder(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`{"b":1, "a":2, "c":3}`, "{b:1,a:2,c:3}"},
+		{`{3:"c", 1:"a", 2:"b"}`, "{3:c,1:a,2:b}"},
+		{`{"a":1, "b":2, "a":3}`, "{a:3,b:2}"},
+		{`{}`, "{}"},
+	}
+
+	for _, tt := range tests{
+		for i := 0; i < 5; i++{
+			eval := testEval(tt.input)
+			if eval.Inspect() != tt.expected{
+				t.Errorf("hash inspect not as expected=%s, got=%s", tt.expected, eval.Inspect())
+				break
+			}
+		}
+	}
+}
+
+
+
+
+
+func TestEvalStructuralEquality(t *testing.T){
+	tests := []struct{
+		input string
+		expected bool
+	}{
+		{"[1,2] == [1,2]", true},
+		{"[1,2] != [1,2]", false},
+		{"[1,2] == [2,1]", false},
+		{"[1,[2,3]] == [1,[2,3]]", true},
+		{"[] == []", true},
+		{`{"a":1, "b":2} == {"b":2, "a":1}`, true},
+		{`{"a":1} == {"a":2}`, false},
+		{`{"a":[1]} == {"a":[1]}`, true},
+		{"let f = fn(x){x}; f == f", true},
+		{"fn(x){x} == fn(x){x}", false},
+		{"[1] == 1", false},
+		{"[1] != true", true},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		testBooleanObject(t, eval, tt.expected)
+	}
+}
+
+func TestEvalArrayHashKeys(t *testing.T){
+	tests := []struct{
+		input string
+		expected interface{}
+	}{
+		{`{[1,2]:"pair"}[[1,2]]`, "pair"},
+		{`let k = [1, "a", true]; {k:5}[[1, "a", true]]`, 5},
+		{`{[1,2]:"pair"}[[2,1]]`, nil},
+		{`{[fn(x){x}]:1}`, "unsuable as a hash map key, expected=integer, string, boolean or array of those , got=ARRAY"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+
+		switch expected := tt.expected.(type){
+		case int:
+			testIntegerObject(t, eval, int64(expected))
+		case string:
+			if _, ok := eval.(*object.Error); ok{
+				testErrorObject(t, eval, expected)
+			}else{
+				testStringObject(t, eval, expected)
+			}
+		default:
+			testNullObject(t, eval)
+		}
+	}
+}
+
+func TestEvalHashKeyCollisions(t *testing.T){
+	original := object.StringHasher
+	object.StringHasher = func(string) uint64 { return 7 }
+	defer func(){ object.StringHasher = original }()
+
+	tests := []struct{
+		input string
+		expected int64
+	}{
+		{`let h = {"one":1, "two":2}; h["one"]`, 1},
+		{`let h = {"one":1, "two":2}; h["two"]`, 2},
+		{`let h = {"one":1, "two":2, "one":3}; h["one"]`, 3},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		testIntegerObject(t, eval, tt.expected)
+	}
+
+	testNullObject(t, testEval(`{"one":1}["three"]`))
+}
+
+func TestEvalOutputAndInput(t *testing.T){
+	input := `let name = input("name? "); print("hi " + name, 1); let next = input(); print(next); input()`
+
+	var out bytes.Buffer
+	ctx := NewContext(&out, strings.NewReader("monkey\r\nlast"))
+	ctx.Enable(CapIO)
+	eval := ctx.Eval(parser.New(lexer.New(input)).ParseProgram(), object.NewEnv())
+
+	testNullObject(t, eval)
+
+	expected := "name? hi monkey\n1\nlast\n"
+	if out.String() != expected{
+		t.Errorf("wrong output, expected=%q, got=%q", expected, out.String())
+	}
+
+	testErrorObject(t, testEval(`input(1)`), "argument for the input builtin not supported, got INTEGER")
+	testErrorObject(t, testEval(`input("a", "b")`), "wrong number of args, expected=0 or 1, got=2")
+}
+
+func TestEvalCancelled(t *testing.T){
+	ctx := NewContext(nil, nil)
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx.SetContext(c)
+
+	eval := ctx.Eval(parser.New(lexer.New(`let f = fn(){ f() }; 1 + 1; f()`)).ParseProgram(), object.NewEnv())
+	errObj, ok := eval.(*object.Error)
+	if !ok{
+		t.Fatalf("expected an error, got=%T (%+v)", eval, eval)
+	}
+
+	if errObj.Cause != context.Canceled || errObj.Message != "evaluation cancelled: context canceled"{
+		t.Errorf("wrong cancellation error, got=%q, cause=%v", errObj.Message, errObj.Cause)
+	}
+
+	//long loops inside builtins look at the cancellation too, not only calls of user functions
+	big := make([]object.Object, 5000)
+	strs := make([]object.Object, 5000)
+	for i := range big{
+		big[i] = &object.Array{Elements: []object.Object{&object.Integer{Value: int64(i)}}}
+		strs[i] = &object.String{Value: "a"}
+	}
+
+	calls := []struct{
+		name string
+		args []object.Object
+	}{
+		{"range", []object.Object{&object.Integer{Value: 5000}}},
+		{"repeat", []object.Object{&object.String{Value: "a"}, &object.Integer{Value: 5000}}},
+		{"flatten", []object.Object{&object.Array{Elements: big}}},
+		{"join", []object.Object{&object.Array{Elements: strs}}},
+		{"sort", []object.Object{&object.Array{Elements: strs}}},
+		{"map", []object.Object{&object.Array{Elements: strs}, ctx.Builtins["len"]}},
+		{"filter", []object.Object{&object.Array{Elements: strs}, ctx.Builtins["len"]}},
+	}
+
+	for _, tt := range calls{
+		eval := ctx.Builtins[tt.name].Fn(ctx, tt.args...)
+		if errObj, ok := eval.(*object.Error); !ok || errObj.Cause != context.Canceled{
+			t.Errorf("expected %s to stop on cancellation, got=%s", tt.name, eval.Inspect())
+		}
+	}
+}
+
+func TestEvalCapabilities(t *testing.T){
+	dir := t.TempDir()
+	input := `write_file(path, "hello"); read_file(path) + " " + read_file(path + "x")`
+
+	ctx := NewContext(nil, nil)
+	env := object.NewEnv()
+	env.Set("path", &object.String{Value: dir + "/out.txt"})
+	program := parser.New(lexer.New(input)).ParseProgram()
+
+	testErrorObject(t, ctx.Eval(program, env), "builtin write_file requires capability fs, which is not enabled")
+
+	ctx.Enable(CapFS)
+	eval := ctx.Eval(program, env)
+	errObj, ok := eval.(*object.Error)
+	if !ok || !strings.HasPrefix(errObj.Message, "could not read file: "){
+		t.Errorf("expected a read error for the missing file, got=%s", eval.Inspect())
+	}
+
+	eval = ctx.Eval(parser.New(lexer.New(`read_file(path)`)).ParseProgram(), env)
+	testStringObject(t, eval, "hello")
+
+	//without a loader imports read the file system, so they need fs as well
+	if err := os.WriteFile(dir+"/lib.monkey", []byte(`export let x = 5;`), 0644); err != nil{
+		t.Fatal(err)
+	}
+	importProgram := parser.New(lexer.New(`import "lib.monkey" as lib; lib.x`)).ParseProgram()
+
+	sandboxed := NewContext(nil, nil)
+	sandboxed.Dir = dir
+	testErrorObject(t, sandboxed.Eval(importProgram, object.NewEnv()), "import lib.monkey requires capability fs, which is not enabled")
+
+	ctx.Dir = dir
+	testIntegerObject(t, ctx.Eval(importProgram, object.NewEnv()), 5)
+
+	if !ctx.Enabled(CapFS) || ctx.Enabled(CapIO){
+		t.Errorf("wrong enabled capabilities, got=%v", ctx.Capabilities())
+	}
+}
+
+func TestEvalModules(t *testing.T){
+	files := map[string]string{
+		"app/lib/math.monkey": `import "util.monkey" as u; export let pi = 3; export fn add(a, b){ a + b } let hidden = 1; export let six = u.double(pi);`,
+		"app/lib/util.monkey": `export fn double(x){ x * 2 }`,
+		"app/a.monkey": `import "b.monkey" as b;`,
+		"app/b.monkey": `import "a.monkey" as a;`,
+		"app/broken.monkey": `export let x = 1 + true;`,
+	}
+
+	loads := map[string]int{}
+	newCtx := func() *Context{
+		ctx := NewContext(nil, nil)
+		ctx.Dir = "app"
+		ctx.Loader = func(path string) (string, error){
+			loads[path]++
+			src, ok := files[path]
+			if !ok{
+				return "", fmt.Errorf("no such file")
+			}
+			return src, nil
+		}
+		return ctx
+	}
+
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`import "lib/math.monkey" as m; m.add(m.pi, m.six)`, "9"},
+		{`import { add, six } from "lib/math.monkey"; add(six, 1)`, "7"},
+		{`import "lib/math.monkey" as m; import "lib/math.monkey" as again; again.pi`, "3"},
+		{`import "lib/math.monkey" as m; m.hidden`, "module lib/math.monkey has no export hidden"},
+		{`import { hidden } from "lib/math.monkey";`, "module lib/math.monkey has no export hidden"},
+		{`import "a.monkey" as a;`, "import cycle: app/a.monkey -> app/b.monkey -> app/a.monkey\n\tat import b.monkey\n\tat import a.monkey"},
+		{`import "missing.monkey" as x;`, "could not import missing.monkey: no such file"},
+		{`import "broken.monkey" as x;`, "type mismatch: INTEGER + BOOLEAN\n\tat import broken.monkey"},
+		{`let x = 5; x.y`, "member access not supported on INTEGER"},
+	}
+
+	for _, tt := range tests{
+		eval := newCtx().Eval(parser.New(lexer.New(tt.input)).ParseProgram(), object.NewEnv())
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %q, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+
+	//a context loads each module once, no matter how often and from where it is imported
+	loads = map[string]int{}
+	ctx := newCtx()
+	for _, input := range []string{`import "lib/math.monkey" as m; import "lib/util.monkey" as u;`, `import { pi } from "lib/math.monkey";`}{
+		if eval := ctx.Eval(parser.New(lexer.New(input)).ParseProgram(), object.NewEnv()); isError(eval){
+			t.Fatalf("unexpected error: %s", eval.Inspect())
+		}
+	}
+	if loads["app/lib/math.monkey"] != 1 || loads["app/lib/util.monkey"] != 1{
+		t.Errorf("expected one load per module in a context, got loads=%v", loads)
+	}
+
+	//a fresh context loads it again
+	newCtx().Eval(parser.New(lexer.New(`import "lib/math.monkey" as m;`)).ParseProgram(), object.NewEnv())
+	if loads["app/lib/math.monkey"] != 2 || loads["app/lib/util.monkey"] != 2{
+		t.Errorf("expected one more load for a fresh context, got loads=%v", loads)
+	}
+}
+
+func TestEvalMemberAccess(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let user = {"name": "ann", "age": 3}; user.name`, "ann"},
+		{`let user = {"name": "ann"}; user.missing`, "null"},
+		{`let h = {"inner": {"x": 5}}; h.inner.x + 1`, "6"},
+		{`"monkey".upper()`, "MONKEY"},
+		{`let s = " a,b "; s.trim().split(",")`, "[a,b]"},
+		{`[3, 1, 2].sort().reverse()`, "[3,2,1]"},
+		{`[1, 2, 3].map(fn(x){ x * 2 }).sum()`, "12"},
+		{`{"a": 1}.keys()`, "[a]"},
+		{`let counter = {"inc": fn(x){ x + 1 }}; counter.inc(1)`, "2"},
+		{`5.name`, "member access not supported on INTEGER"},
+		{`"a".shout()`, "no method shout for STRING"},
+		{`"a".read_file()`, "builtin read_file requires capability fs, which is not enabled"},
+		{`"a".repeat()`, "wrong number of args, expected=2, got=1"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %q, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
 		}
+	}
+}
 
-		testIntegerObject(t, pairs.Value, expectedValue)
+func TestEvalOptionalChaining(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`let h = {"a": {"b": 1}}; h["a"]?["b"]`, "1"},
+		{`let h = {}; h["a"]?["b"]`, "null"},
+		{`let h = {}; h["a"]?["b"]["c"].d`, "null"},
+		{`let h = {}; h.a?.b.c[0]`, "null"},
+		{`let h = {}; h.a?.upper()`, "null"},
+		{`let h = {}; h.a?[1:]`, "null"},
+		{`let h = {"a": "hi"}; h.a?.upper()`, "HI"},
+		{`let h = {}; h.a?.b == null_value`, "variable not found: null_value"},
+		{`let h = {}; len([h.a?.b])`, "1"},
+		{`let h = {}; h["a"]["b"]`, "index operator not supported, got =object.ObjectType"},
+		{`let h = {}; let calls = fn(){ 1 + true }; h.a?[calls()]`, "null"},
+		{`let h = {}; h.a ?? "default"`, "default"},
+		{`let h = {"a": false}; h.a ?? "default"`, "false"},
+		{`let h = {}; h.a?.b ?? h.c ?? 3`, "3"},
+		{`1 ?? (1 + true)`, "1"},
+		{`let h = {}; h.a ?? 1 + 2`, "3"},
+		{`(1 + true) ?? 1`, "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %q, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
 	}
 }
 
+func TestEvalTernaryAndArrows(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`1 < 2 ? "yes" : "no"`, "yes"},
+		{`1 > 2 ? "yes" : "no"`, "no"},
+		{`let sign = fn(n){ n > 0 ? 1 : n < 0 ? -1 : 0 }; [sign(5), sign(-5), sign(0)]`, "[1,-1,0]"},
+		{`false ? 1 + true : 2`, "2"},
+		{`(1 + true) ? 1 : 2`, "type mismatch: INTEGER + BOOLEAN"},
+		{`let h = {}; h.a ? 1 : 2`, "2"},
+		{`let double = x => x * 2; double(21)`, "42"},
+		{`let add = (a, b) => a + b; add(1, 2)`, "3"},
+		{`map([1, 2, 3], x => x * x)`, "[1,4,9]"},
+		{`let f = (x) => { let y = x + 1; y * 2 }; f(1)`, "4"},
+		{`let f = (a, b = 10, ...rest) => [a, b, rest]; f(1)`, "[1,10,[]]"},
+		{`let f = (...xs) => len(xs); f(1, 2, 3)`, "3"},
+		{`let adder = x => y => x + y; adder(1)(2)`, "3"},
+		{`let pick = fn(ok){ ok ? x => x + 1 : x => x - 1 }; [pick(true)(1), pick(false)(1)]`, "[2,0]"},
+		{`(() => 7)()`, "7"},
+	}
 
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %q, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
 
+func TestEvalPipeline(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`[1, 2, 3] |> map(fn(x){ x * 2 }) |> len`, "3"},
+		{`[1, 2, 3, 4] |> filter(x => x > 2) |> map(x => x * 10)`, "[30,40]"},
+		{`let sum = fn(xs){ reduce(xs, fn(acc, x){ acc + x }, 0) }; range(5) |> sum`, "10"},
+		{`"monkey" |> capitalize_me`, "variable not found: capitalize_me"},
+		{`let h = {"f": fn(x){ x + 1 }}; 1 |> h.f`, "2"},
+		{`let add = fn(a, b){ a + b }; 1 + 1 |> add(40)`, "42"},
+		{`5 |> (x => x * x)`, "25"},
+		{`(1 + true) |> len`, "type mismatch: INTEGER + BOOLEAN"},
+	}
 
+	for _, tt := range tests{
+		eval := testEval(tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %q, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
 
 //helpers
 func testNullObject(t *testing.T, eval object.Object) bool{
@@ -441,6 +1338,21 @@ func testStringObject(t *testing.T, eval object.Object, expected string) bool{
 }
 
 
+func testErrorObject(t *testing.T, eval object.Object, expected string) bool{
+	result, ok := eval.(*object.Error)
+	if !ok{
+		t.Errorf("object is not error , got=%T (%+v)", eval, eval)
+		return false
+	}
+
+	if result.Message != expected{
+		t.Errorf("wrong error message, expected=%q , got=%q", expected, result.Message)
+		return false
+	}
+
+	return true
+}
+
 func testBooleanObject(t *testing.T, eval object.Object, expected bool) bool{
 	result, ok := eval.(*object.Boolean)
 	if !ok{
@@ -0,0 +1,194 @@
+package evaluation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/singlaanish56/Interpreter-In-Go/ast"
+	"github.com/singlaanish56/Interpreter-In-Go/lexer"
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+	"github.com/singlaanish56/Interpreter-In-Go/parser"
+	"github.com/singlaanish56/Interpreter-In-Go/stdlib"
+)
+
+//returns the source of the module at the resolved path
+type ModuleLoader func(path string) (string, error)
+
+func readModuleFile(path string) (string, error){
+	content, err := os.ReadFile(path)
+	return string(content), err
+}
+
+func (ctx *Context) evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object{
+	module, err := ctx.importModule(node.Path)
+	if err != nil{
+		return err
+	}
+
+	if node.Alias != nil{
+		return env.Set(node.Alias.Value, module)
+	}
+
+	for _, name := range node.Names{
+		value := moduleExport(module, name.Value)
+		if isError(value){
+			return value
+		}
+		env.Set(name.Value, value)
+	}
+
+	return module
+}
+
+//every module is evaluated once per context in its own environment, later imports get the cached module
+func (ctx *Context) importModule(path string) (*object.Module, *object.Error){
+	resolved := ctx.resolveModule(path)
+
+	for i, importing := range ctx.importing{
+		if importing == resolved{
+			cycle := append(append([]string{}, ctx.importing[i:]...), resolved)
+			return nil, newError("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if module, ok := ctx.modules[resolved]; ok{
+		return module, nil
+	}
+
+	if !strings.HasPrefix(resolved, stdlib.ImportPrefix) && ctx.Loader == nil && !ctx.Enabled(CapFS){
+		return nil, newError("import %s requires capability %s, which is not enabled", path, CapFS)
+	}
+
+	src, readErr := ctx.moduleSource(resolved)
+	if readErr != nil{
+		return nil, newError("could not import %s: %s", path, readErr)
+	}
+
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0{
+		msgs := []string{}
+		for _, err := range p.Errors(){
+			msgs = append(msgs, err.Error())
+		}
+		return nil, newError("could not import %s, parser errors: %s", path, strings.Join(msgs, "; "))
+	}
+
+	ctx.importing = append(ctx.importing, resolved)
+	defer func(){ ctx.importing = ctx.importing[:len(ctx.importing)-1] }()
+
+	//user modules see the stdlib prelude like the script does, the stdlib modules build it so they start empty
+	env := object.NewEnv()
+	if !strings.HasPrefix(resolved, stdlib.ImportPrefix){
+		prelude, err := ctx.Prelude()
+		if err != nil{
+			return nil, err
+		}
+		env = object.NewEnclosedEnvironment(prelude)
+	}
+	if result := ctx.Eval(program, env); isError(result){
+		err := result.(*object.Error)
+		err.AddFrame("import "+path)
+		return nil, err
+	}
+
+	module := &object.Module{Name: path, Exports: map[string]object.Object{}}
+	for _, statement := range program.Statements{
+		if export, ok := statement.(*ast.ExportStatement); ok{
+			module.Exports[export.Name()], _ = env.Get(export.Name())
+		}
+	}
+
+	ctx.modules[resolved] = module
+	return module, nil
+}
+
+//stdlib modules come from the binary, everything else from the loader or the file system
+func (ctx *Context) moduleSource(resolved string) (string, error){
+	if name, ok := strings.CutPrefix(resolved, stdlib.ImportPrefix); ok{
+		src, found := stdlib.Source(name)
+		if !found{
+			return "", fmt.Errorf("no stdlib module %s, have %s", name, strings.Join(stdlib.Names(), ", "))
+		}
+		return src, nil
+	}
+
+	if ctx.Loader == nil{
+		return readModuleFile(resolved)
+	}
+
+	return ctx.Loader(resolved)
+}
+
+//an environment holding the exports of every stdlib module. it is built once per context and
+//meant to be the outer environment of the script, so the script's own bindings shadow the stdlib
+func (ctx *Context) Prelude() (*object.Environment, *object.Error){
+	if ctx.prelude != nil{
+		return ctx.prelude, nil
+	}
+
+	prelude := object.NewEnv()
+	for _, name := range stdlib.Names(){
+		module, err := ctx.importModule(stdlib.ImportPrefix + name)
+		if err != nil{
+			return nil, err
+		}
+
+		for export, value := range module.Exports{
+			prelude.Set(export, value)
+		}
+	}
+
+	ctx.prelude = prelude
+	return prelude, nil
+}
+
+//relative paths resolve against the directory of the importing module, or the context directory at the top level
+func (ctx *Context) resolveModule(path string) string{
+	if strings.HasPrefix(path, stdlib.ImportPrefix){
+		return path
+	}
+
+	if filepath.IsAbs(path){
+		return filepath.Clean(path)
+	}
+
+	dir := ctx.Dir
+	if len(ctx.importing) > 0{
+		dir = filepath.Dir(ctx.importing[len(ctx.importing)-1])
+	}
+
+	return filepath.Join(dir, path)
+}
+
+//`h.name` is `h["name"]` for hashes, modules only expose what they exported
+func (ctx *Context) evalMemberExpression(node *ast.MemberExpression, env *object.Environment) object.Object{
+	left := ctx.evalChained(node.Left, env)
+	if isError(left){
+		return left
+	}
+	if shortCircuits(left, node.Optional){
+		return shortCircuit
+	}
+
+	switch left := left.(type){
+	case *object.Module:
+		return moduleExport(left, node.Property.Value)
+	case *object.Hash:
+		return evalHashIndexExpression(left, &object.String{Value: node.Property.Value})
+	default:
+		return newError("member access not supported on %s", left.Type())
+	}
+}
+
+//the value the module exported under name, anything it did not export is unreachable from outside
+func moduleExport(module *object.Module, name string) object.Object{
+	value, ok := module.Exports[name]
+	if !ok{
+		return newError("module %s has no export %s", module.Name, name)
+	}
+
+	return value
+}
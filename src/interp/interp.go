@@ -0,0 +1,213 @@
+//entry point for go programs embedding the interpreter, it wires the lexer, parser and evaluator together
+package interp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/singlaanish56/Interpreter-In-Go/evaluation"
+	"github.com/singlaanish56/Interpreter-In-Go/lexer"
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+	"github.com/singlaanish56/Interpreter-In-Go/parser"
+)
+
+//keeps its globals between runs, so a later Run sees what an earlier one defined
+type Interpreter struct{
+	ctx *evaluation.Context
+	env *object.Environment
+}
+
+type config struct{
+	out io.Writer
+	in io.Reader
+	limits evaluation.Limits
+	maxDepth int
+	capabilities []evaluation.Capability
+	moduleDir string
+	loader evaluation.ModuleLoader
+}
+
+type Option func(*config)
+
+//where print writes to, stdout by default
+func WithOutput(out io.Writer) Option{
+	return func(c *config){
+		c.out = out
+	}
+}
+
+//where input reads from, stdin by default
+func WithInput(in io.Reader) Option{
+	return func(c *config){
+		c.in = in
+	}
+}
+
+//caps the steps and allocations of every single Run, hitting one fails the run with evaluation.ErrResourceExhausted
+func WithLimits(limits evaluation.Limits) Option{
+	return func(c *config){
+		c.limits = limits
+	}
+}
+
+//how deep script functions may call into each other, evaluation.DefaultMaxDepth by default.
+//set it too high and deep recursion crashes the host with a go stack overflow
+func WithMaxDepth(depth int) Option{
+	return func(c *config){
+		c.maxDepth = depth
+	}
+}
+
+//enables the builtins of the capabilities, without it scripts cannot print, read input or touch the outside world
+func WithCapabilities(capabilities ...evaluation.Capability) Option{
+	return func(c *config){
+		c.capabilities = append(c.capabilities, capabilities...)
+	}
+}
+
+//the directory relative imports of the scripts resolve against, the working directory by default.
+//reading modules from the file system also needs evaluation.CapFS
+func WithModuleDir(dir string) Option{
+	return func(c *config){
+		c.moduleDir = dir
+	}
+}
+
+//reads imported modules from somewhere other than the file system, it works without evaluation.CapFS
+func WithModuleLoader(loader evaluation.ModuleLoader) Option{
+	return func(c *config){
+		c.loader = loader
+	}
+}
+
+//fails only if the embedded stdlib prelude cannot be loaded, the error is a RuntimeError then
+func New(opts ...Option) (*Interpreter, error){
+	c := &config{out: os.Stdout, in: os.Stdin}
+	for _, opt := range opts{
+		opt(c)
+	}
+
+	ctx := evaluation.NewContext(c.out, c.in)
+	ctx.Enable(c.capabilities...)
+	ctx.Dir = c.moduleDir
+	if c.maxDepth > 0{
+		ctx.MaxDepth = c.maxDepth
+	}
+	if c.loader != nil{
+		ctx.Loader = c.loader
+	}
+
+	prelude, err := ctx.Prelude()
+	if err != nil{
+		return nil, fmt.Errorf("loading the stdlib prelude: %w", &RuntimeError{Object: err})
+	}
+
+	//set after the prelude so loading it does not eat into the budget of the scripts
+	ctx.Limits = c.limits
+
+	return &Interpreter{
+		ctx: ctx,
+		env: object.NewEnclosedEnvironment(prelude),
+	}, nil
+}
+
+//the source failed to parse, holds every error the parser ran into
+type ParseError struct{
+	Errors []error
+}
+
+func (e *ParseError) Error() string{
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors{
+		msgs[i] = err.Error()
+	}
+
+	return "parser errors:\n\t" + strings.Join(msgs, "\n\t")
+}
+
+//the script evaluated to an error object
+type RuntimeError struct{
+	Object *object.Error
+}
+
+func (e *RuntimeError) Error() string{
+	return e.Object.Inspect()
+}
+
+//lets errors.Is match context.Canceled, context.DeadlineExceeded and evaluation.ErrResourceExhausted
+func (e *RuntimeError) Unwrap() error{
+	return e.Object.Cause
+}
+
+//parses and evaluates the source, the result is the value of the last statement or NULL
+func (i *Interpreter) Run(src string) (object.Object, error){
+	return i.RunContext(context.Background(), src)
+}
+
+//like Run, but the evaluation stops with a RuntimeError wrapping c.Err() once c is done
+func (i *Interpreter) RunContext(c context.Context, src string) (result object.Object, err error){
+	i.ctx.SetContext(c)
+	defer i.ctx.SetContext(nil)
+	i.ctx.ResetUsage()
+
+	//a bug in the evaluator or in a host builtin fails the run instead of taking the host down
+	defer func(){
+		if r := recover(); r != nil{
+			result, err = nil, &RuntimeError{Object: &object.Error{Message: fmt.Sprintf("internal error: %v", r)}}
+		}
+	}()
+
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0{
+		return nil, &ParseError{Errors: p.Errors()}
+	}
+
+	result = i.ctx.Eval(program, i.env)
+	if errObj, ok := result.(*object.Error); ok{
+		return nil, &RuntimeError{Object: errObj}
+	}
+
+	if result == nil{
+		return evaluation.NULL, nil
+	}
+
+	return result, nil
+}
+
+//the steps and allocations of the last run
+func (i *Interpreter) Usage() evaluation.Usage{
+	return i.ctx.Usage()
+}
+
+func (i *Interpreter) SetGlobal(name string, value object.Object){
+	i.env.Set(name, value)
+}
+
+func (i *Interpreter) GetGlobal(name string) (object.Object, bool){
+	return i.env.Get(name)
+}
+
+//makes fn callable from scripts under the name, replacing a default builtin of the same name
+func (i *Interpreter) RegisterBuiltin(name string, fn object.BuiltinFunction){
+	i.ctx.Builtins[name] = &object.Builtin{Fn: fn}
+}
+
+//registers a plain go function, arguments and results are converted with object.ToGo and object.FromGo
+func (i *Interpreter) RegisterFunc(name string, fn any) error{
+	if fn == nil || reflect.TypeOf(fn).Kind() != reflect.Func{
+		return fmt.Errorf("%s has to be a function, got %T", name, fn)
+	}
+
+	obj, err := object.FromGo(fn)
+	if err != nil{
+		return err
+	}
+
+	i.ctx.Builtins[name] = obj.(*object.Builtin)
+	return nil
+}
@@ -0,0 +1,285 @@
+package interp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/singlaanish56/Interpreter-In-Go/evaluation"
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+)
+
+func TestRunKeepsGlobals(t *testing.T){
+	var out bytes.Buffer
+	in := newInterpreter(t, WithOutput(&out), WithInput(strings.NewReader("world")), WithCapabilities(evaluation.CapIO))
+
+	if _, err := in.Run(`let greet = fn(name){ "hello " + name }; let x = 2;`); err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := in.Run(`print(greet(input())); x * 21`)
+	if err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result.Inspect() != "42"{
+		t.Errorf("wrong result, expected=42, got=%s", result.Inspect())
+	}
+
+	if out.String() != "hello world\n"{
+		t.Errorf("wrong output, expected=%q, got=%q", "hello world\n", out.String())
+	}
+}
+
+func TestRunErrors(t *testing.T){
+	in := newInterpreter(t)
+
+	_, err := in.Run(`let = 5;`)
+	if _, ok := err.(*ParseError); !ok{
+		t.Errorf("expected a parse error, got=%T (%v)", err, err)
+	}
+
+	_, err = in.Run(`1 + true`)
+	runtimeErr, ok := err.(*RuntimeError)
+	if !ok{
+		t.Fatalf("expected a runtime error, got=%T (%v)", err, err)
+	}
+	if runtimeErr.Object.Message != "type mismatch: INTEGER + BOOLEAN"{
+		t.Errorf("wrong error message, got=%q", runtimeErr.Object.Message)
+	}
+
+	result, err := in.Run(``)
+	if err != nil || result.Type() != object.NULL_VAL{
+		t.Errorf("expected NULL for an empty program, got=%v, %v", result, err)
+	}
+
+	if _, err := in.Run(`mod(7, 0)`); err == nil || err.Error() != "division by zero\n\tat mod"{
+		t.Errorf("wrong error for a division by zero, got=%v", err)
+	}
+
+	//a panicking host builtin fails the run, the interpreter stays usable
+	in.RegisterBuiltin("explode", func(rt object.Runtime, args ...object.Object) object.Object{
+		panic("boom")
+	})
+	if _, err := in.Run(`explode()`); err == nil || err.Error() != "internal error: boom"{
+		t.Errorf("wrong error for a panic, got=%v", err)
+	}
+	if result, err := in.Run(`1 + 1`); err != nil || result.Inspect() != "2"{
+		t.Errorf("expected the interpreter to be usable after a panic, got=%v, %v", result, err)
+	}
+}
+
+func TestGlobalsAndBuiltins(t *testing.T){
+	in := newInterpreter(t)
+	in.SetGlobal("limit", &object.Integer{Value: 10})
+	in.RegisterBuiltin("double", func(rt object.Runtime, args ...object.Object) object.Object{
+		return &object.Integer{Value: args[0].(*object.Integer).Value * 2}
+	})
+
+	if _, err := in.Run(`let result = double(limit);`); err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, ok := in.GetGlobal("result")
+	if !ok || result.Inspect() != "20"{
+		t.Errorf("wrong global, expected=20, got=%v", result)
+	}
+
+	//the stdlib prelude is there without an import
+	if result, err := in.Run(`capitalize("monkey")`); err != nil || result.Inspect() != "Monkey"{
+		t.Errorf("expected the stdlib prelude, got=%v, %v", result, err)
+	}
+
+	//registering on one interpreter leaves the others alone
+	if _, err := newInterpreter(t).Run(`double(1)`); err == nil{
+		t.Errorf("expected double to be unknown in a fresh interpreter")
+	}
+}
+
+func TestRegisterFunc(t *testing.T){
+	in := newInterpreter(t)
+	err := in.RegisterFunc("tags", func(name string, n int) ([]string, error){
+		if n < 0{
+			return nil, errors.New("n cannot be negative")
+		}
+		return strings.Split(strings.Repeat(name+",", n), ",")[:n], nil
+	})
+	if err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := in.Run(`tags("a", 2)`)
+	if err != nil || result.Inspect() != "[a,a]"{
+		t.Errorf("wrong result, expected=[a,a], got=%v, %v", result, err)
+	}
+
+	if _, err := in.Run(`tags("a", -1)`); err == nil || err.Error() != "n cannot be negative"{
+		t.Errorf("wrong error, got=%v", err)
+	}
+
+	if err := in.RegisterFunc("bad", 42); err == nil{
+		t.Errorf("expected an error registering a non function")
+	}
+}
+
+func TestRunContextCancels(t *testing.T){
+	in := newInterpreter(t)
+
+	c, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := in.RunContext(c, `let spin = fn(n){ if (n == 0) { 0 } else { spin(n-1) } }; map(range(100000), fn(x){ map(range(100000), fn(y){ spin(10) }) })`)
+	if !errors.Is(err, context.DeadlineExceeded){
+		t.Fatalf("expected a deadline error, got=%v", err)
+	}
+
+	//the deadline only applies to that run
+	result, err := in.Run(`spin(3)`)
+	if err != nil || result.Inspect() != "0"{
+		t.Errorf("expected the interpreter to be usable after a cancelled run, got=%v, %v", result, err)
+	}
+
+	//runaway recursion fails on the call depth long before the go stack runs out
+	c, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = in.RunContext(c, `fn f(n) { f(n + 1) } f(0)`)
+	if err == nil || err.Error() != "maximum call depth of 10000 exceeded\n\tat f (x10000)"{
+		t.Errorf("wrong error for runaway recursion, got=%v", err)
+	}
+
+	_, err = newInterpreter(t, WithMaxDepth(3)).Run(`let down = fn(n){ if (n == 0) { 0 } else { down(n - 1) } }; down(2); down(3)`)
+	if err == nil || !strings.HasPrefix(err.Error(), "maximum call depth of 3 exceeded"){
+		t.Errorf("wrong error for a custom call depth, got=%v", err)
+	}
+}
+
+func TestRunLimits(t *testing.T){
+	in := newInterpreter(t, WithLimits(evaluation.Limits{MaxSteps: 1000, MaxAllocated: 1000}))
+
+	_, err := in.Run(`let f = fn(n){ f(n+1) }; f(0)`)
+	if !errors.Is(err, evaluation.ErrResourceExhausted){
+		t.Fatalf("expected the step budget to run out, got=%v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "resource exhausted: step budget of 1000 exceeded"){
+		t.Errorf("wrong error message, got=%q", err.Error())
+	}
+
+	_, err = in.Run(`let grow = fn(s){ if (len(s) > 2000) { s } else { grow(s + s) } }; grow("ab")`)
+	if !errors.Is(err, evaluation.ErrResourceExhausted) || !strings.HasPrefix(err.Error(), "resource exhausted: allocation budget of 1000 bytes exceeded"){
+		t.Fatalf("expected the allocation budget to run out, got=%v", err)
+	}
+
+	//builtins check the budget before they build a big result, not after
+	big := []string{
+		`repeat("ab", 1000000)`,
+		`range(0, 1000000)`,
+		`format("{:1000000}", 1)`,
+		`json_stringify([[[1]]], 1000000)`,
+		`let a = range(100); concat(a, a, a)`,
+		`let a = range(100); flatten([a, a, a, a])`,
+	}
+	for _, src := range big{
+		if _, err := in.Run(src); !errors.Is(err, evaluation.ErrResourceExhausted){
+			t.Errorf("expected the allocation budget to stop %s, got=%v", src, err)
+		}
+	}
+
+	//handing back an existing string is not a new allocation
+	reuse := `let s = repeat("a", 100); let a = [s, s];` + strings.Repeat(` first(a); last(a);`, 20)
+	if _, err := in.Run(reuse); err != nil{
+		t.Fatalf("unexpected error for builtins returning their arguments: %s", err)
+	}
+
+	if _, err := in.Run(`push_back([1, 2], 3)`); err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	//usage only covers the last run and stays within the limits
+	usage := in.Usage()
+	if usage.Steps <= 0 || usage.Steps > 1000 || usage.Allocated <= 0 || usage.Allocated > 1000{
+		t.Errorf("wrong usage, got=%+v", usage)
+	}
+
+	if _, err := in.Run(`push_back(push_back([1, 2], 3), 4)`); err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if more := in.Usage(); more.Steps <= usage.Steps || more.Allocated <= usage.Allocated{
+		t.Errorf("expected a bigger run to use more, got=%+v after %+v", more, usage)
+	}
+}
+
+func TestCapabilities(t *testing.T){
+	_, err := newInterpreter(t).Run(`print(1)`)
+	if err == nil || err.Error() != "builtin print requires capability io, which is not enabled"{
+		t.Errorf("wrong error for a disabled builtin, got=%v", err)
+	}
+
+	t.Setenv("MONKEY_TEST_VAR", "banana")
+	in := newInterpreter(t, WithCapabilities(evaluation.CapEnv, evaluation.CapRand))
+
+	result, err := in.Run(`[getenv("MONKEY_TEST_VAR"), getenv("MONKEY_TEST_MISSING"), random(5, 6)]`)
+	if err != nil || result.Inspect() != "[banana,null,5]"{
+		t.Errorf("wrong result, got=%v, %v", result, err)
+	}
+
+	if _, err := in.Run(`now()`); err == nil || err.Error() != "builtin now requires capability time, which is not enabled"{
+		t.Errorf("wrong error for a disabled builtin, got=%v", err)
+	}
+
+	//a host builtin is available no matter which capabilities are enabled
+	in.RegisterBuiltin("now", func(rt object.Runtime, args ...object.Object) object.Object{
+		return &object.Integer{Value: 7}
+	})
+	if result, err := in.Run(`now()`); err != nil || result.Inspect() != "7"{
+		t.Errorf("wrong result for a host builtin, got=%v, %v", result, err)
+	}
+}
+
+func TestRunModules(t *testing.T){
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.monkey"), []byte(`export fn greet(name){ "hi " + name }`), 0644); err != nil{
+		t.Fatal(err)
+	}
+
+	if _, err := newInterpreter(t, WithModuleDir(dir)).Run(`import "greet.monkey" as g;`); err == nil || err.Error() != "import greet.monkey requires capability fs, which is not enabled"{
+		t.Errorf("expected file imports to need fs, got=%v", err)
+	}
+
+	result, err := newInterpreter(t, WithModuleDir(dir), WithCapabilities(evaluation.CapFS)).Run(`import { greet } from "greet.monkey"; greet("bob")`)
+	if err != nil || result.Inspect() != "hi bob"{
+		t.Errorf("wrong result, got=%v, %v", result, err)
+	}
+
+	loader := func(path string) (string, error){
+		return `export let answer = 42;`, nil
+	}
+	result, err = newInterpreter(t, WithModuleLoader(loader)).Run(`import "anything" as lib; lib.answer`)
+	if err != nil || result.Inspect() != "42"{
+		t.Errorf("wrong result, got=%v, %v", result, err)
+	}
+
+	//imported modules get the same stdlib prelude as the script
+	loader = func(path string) (string, error){
+		return `export fn label(n){ pad_left(str(n), 3, "0") }`, nil
+	}
+	result, err = newInterpreter(t, WithModuleLoader(loader)).Run(`import { label } from "labels"; label(7)`)
+	if err != nil || result.Inspect() != "007"{
+		t.Errorf("wrong result, got=%v, %v", result, err)
+	}
+}
+
+func newInterpreter(t *testing.T, opts ...Option) *Interpreter{
+	t.Helper()
+
+	in, err := New(opts...)
+	if err != nil{
+		t.Fatalf("could not create the interpreter: %s", err)
+	}
+
+	return in
+}
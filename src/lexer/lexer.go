@@ -19,11 +19,17 @@ func New(input string) *Lexer {
 }
 
 func (lexer *Lexer) peekChar() rune{
-	if lexer.currentPostion >= len(lexer.input){
+	return lexer.peekNthChar(1)
+}
+
+// peekNthChar looks n characters past the current one without consuming anything
+func (lexer *Lexer) peekNthChar(n int) rune{
+	pos := lexer.currentPostion + n
+	if pos >= len(lexer.input){
 		return 0
 	}
 
-	return lexer.input[lexer.nextReadPosition]
+	return lexer.input[pos]
 }
 
 func (lexer *Lexer) nextChar() {
@@ -62,6 +68,7 @@ func (lexer *Lexer) GetToken() token.Token{
 		
 		start := lexer.currentPostion+1
 		var strBuilder []rune
+		var tokenType token.TokenType = token.STRING
 		for{
 			lexer.nextChar()
 
@@ -72,22 +79,36 @@ func (lexer *Lexer) GetToken() token.Token{
 				continue
 			}
 
+			//keep `${...}` as it is, quotes inside the embedded expression do not end the string
+			if lexer.char == '$' && lexer.peekChar() == '{'{
+				tokenType = token.INTERPOLATED
+				end := embeddedExpressionEnd(lexer.input, lexer.currentPostion)
+				for lexer.currentPostion < end && lexer.char != 0{
+					strBuilder = append(strBuilder, lexer.char)
+					lexer.nextChar()
+				}
+
+				if lexer.char == 0{
+					break
+				}
+			}
+
 			strBuilder=append(strBuilder, lexer.char)
 		}
 
 		str := string(strBuilder)
 		endIndex := lexer.currentPostion
 		lexer.nextChar()
-		return token.Token{Type:token.STRING, Identifier: str, StartPosition: start, EndPosition: endIndex}
+		return token.Token{Type:tokenType, Identifier: str, StartPosition: start, EndPosition: endIndex}
 	}
 
 
-	//any variable names or keywords
-	if((lexer.char>='a' && lexer.char<='z') || (lexer.char>='A' && lexer.char<='Z')){
+	//any variable names or keywords, they start with a letter and can go on with letters, digits or underscores
+	if isLetter(lexer.char){
 		
 		start := lexer.currentPostion
 	
-		for (lexer.char>='a' && lexer.char<='z') || (lexer.char>='A' && lexer.char<='Z'){
+		for isLetter(lexer.char) || isDigit(lexer.char) || lexer.char == '_'{
 			lexer.nextChar()
 		}
 
@@ -112,6 +133,9 @@ func (lexer *Lexer) GetToken() token.Token{
 			str+=string(lexer.char)	
 			tk = token.Token{Type: token.DOUBLEEQUALTO, Identifier: str, StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
 
+		}else if lexer.peekChar() == '>'{
+			lexer.nextChar()
+			tk = token.Token{Type: token.ARROW, Identifier: "=>", StartPosition: lexer.currentPostion-1, EndPosition: lexer.currentPostion+1}
 		}else{
 			tk = token.Token{Type: token.EQUALTO, Identifier: str, StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
 		}
@@ -150,6 +174,36 @@ func (lexer *Lexer) GetToken() token.Token{
 		}
 	case '-':
 		tk = token.Token{Type: token.MINUS, Identifier: string(lexer.char), StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
+	case '.':
+		if lexer.peekChar() == '.' && lexer.peekNthChar(2) == '.'{
+			start := lexer.currentPostion
+			lexer.nextChar()
+			lexer.nextChar()
+			tk = token.Token{Type: token.ELLIPSIS, Identifier: "...", StartPosition: start, EndPosition: lexer.currentPostion+1}
+		}else{
+			tk = token.Token{Type: token.DOT, Identifier: string(lexer.char), StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
+		}
+	case '?':
+		switch lexer.peekChar(){
+		case '.':
+			lexer.nextChar()
+			tk = token.Token{Type: token.OPTIONALDOT, Identifier: "?.", StartPosition: lexer.currentPostion-1, EndPosition: lexer.currentPostion+1}
+		case '[':
+			lexer.nextChar()
+			tk = token.Token{Type: token.OPTIONALINDEX, Identifier: "?[", StartPosition: lexer.currentPostion-1, EndPosition: lexer.currentPostion+1}
+		case '?':
+			lexer.nextChar()
+			tk = token.Token{Type: token.COALESCE, Identifier: "??", StartPosition: lexer.currentPostion-1, EndPosition: lexer.currentPostion+1}
+		default:
+			tk = token.Token{Type: token.QUESTION, Identifier: string(lexer.char), StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
+		}
+	case '|':
+		if lexer.peekChar() == '>'{
+			lexer.nextChar()
+			tk = token.Token{Type: token.PIPE, Identifier: "|>", StartPosition: lexer.currentPostion-1, EndPosition: lexer.currentPostion+1}
+		}else{
+			tk = token.Token{Type: token.INV, Identifier: string(lexer.char), StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
+		}
 	case '/':
 		tk = token.Token{Type: token.DIVIDE, Identifier: string(lexer.char), StartPosition: lexer.currentPostion, EndPosition: lexer.currentPostion+1}
 	case '*':
@@ -177,3 +231,75 @@ func (lexer *Lexer) GetToken() token.Token{
 func isEsapceSequence(currentChar rune) bool{
 	return currentChar==' ' || currentChar=='\n' || currentChar=='\t' || currentChar=='\r'
 }
+
+func isLetter(currentChar rune) bool{
+	return (currentChar>='a' && currentChar<='z') || (currentChar>='A' && currentChar<='Z')
+}
+
+func isDigit(currentChar rune) bool{
+	return currentChar>='0' && currentChar<='9'
+}
+
+//position of the `}` closing the `${` that begins at start, len(input) if it is never closed.
+//nested braces and string literals inside the expression are skipped over
+func embeddedExpressionEnd(input []rune, start int) int{
+	depth := 0
+	for i := start+1; i < len(input); i++{
+		switch input[i]{
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0{
+				return i
+			}
+		case '"':
+			for i++; i < len(input) && input[i] != '"'; i++{
+				if input[i] == '$' && i+1 < len(input) && input[i+1] == '{'{
+					i = embeddedExpressionEnd(input, i)
+				}
+			}
+		}
+	}
+
+	return len(input)
+}
+
+type InterpolationPart struct{
+	Value string
+	IsExpression bool
+}
+
+//splits the body of an interpolated string into text and the source of the embedded expressions,
+//returns false when a `${` is never closed
+func SplitInterpolated(raw string) ([]InterpolationPart, bool){
+	input := []rune(raw)
+	parts := []InterpolationPart{}
+
+	var text []rune
+	for i := 0; i < len(input); i++{
+		if input[i] == '$' && i+1 < len(input) && input[i+1] == '{'{
+			end := embeddedExpressionEnd(input, i)
+			if end >= len(input){
+				return nil, false
+			}
+
+			if len(text) > 0{
+				parts = append(parts, InterpolationPart{Value: string(text)})
+				text = nil
+			}
+
+			parts = append(parts, InterpolationPart{Value: string(input[i+2:end]), IsExpression: true})
+			i = end
+			continue
+		}
+
+		text = append(text, input[i])
+	}
+
+	if len(text) > 0{
+		parts = append(parts, InterpolationPart{Value: string(text)})
+	}
+
+	return parts, true
+}
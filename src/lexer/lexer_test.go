@@ -108,4 +108,185 @@ func TestNextToken(t *testing.T){
 
 		fmt.Printf("tokenizeliteral : %q\n", tt.expectedLiteral)
 	}
-}
\ No newline at end of file
+}
+
+func TestEllipsisToken(t *testing.T){
+	input := `fn(a, b=10, ...rest){} f(...arr);.`
+
+	tests:=[]struct{
+		expectedType token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "fn"},
+		{token.OROUNDBR, "("},
+		{token.VARIABLE, "a"},
+		{token.COMMA, ","},
+		{token.VARIABLE, "b"},
+		{token.EQUALTO, "="},
+		{token.NUMBER, "10"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.VARIABLE, "rest"},
+		{token.CROUNDBR, ")"},
+		{token.OCURLYBR, "{"},
+		{token.CCURLYBR, "}"},
+		{token.VARIABLE, "f"},
+		{token.OROUNDBR, "("},
+		{token.ELLIPSIS, "..."},
+		{token.VARIABLE, "arr"},
+		{token.CROUNDBR, ")"},
+		{token.SEMICOLON, ";"},
+		{token.DOT, "."},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests{
+		tok := lexer.GetToken()
+
+		if tok.Type !=tt.expectedType{
+			t.Fatalf("tests[%d] - token type wrong, expected=%q, got=%q, expecedliteral=%q",i,tt.expectedType, tok.Type, tt.expectedLiteral)
+		}
+		if tok.Identifier !=tt.expectedLiteral{
+			t.Fatalf("tests[%d] - literal type wrong, expected=%q, got=%q",i,tt.expectedLiteral, tok.Identifier)
+		}
+	}
+}
+
+func TestConditionalTokens(t *testing.T){
+	input := `a ? b : c; x => x == y; h?.a?[0] ?? d |> f |`
+
+	tests:=[]struct{
+		expectedType token.TokenType
+		expectedLiteral string
+	}{
+		{token.VARIABLE, "a"},
+		{token.QUESTION, "?"},
+		{token.VARIABLE, "b"},
+		{token.COLON, ":"},
+		{token.VARIABLE, "c"},
+		{token.SEMICOLON, ";"},
+		{token.VARIABLE, "x"},
+		{token.ARROW, "=>"},
+		{token.VARIABLE, "x"},
+		{token.DOUBLEEQUALTO, "=="},
+		{token.VARIABLE, "y"},
+		{token.SEMICOLON, ";"},
+		{token.VARIABLE, "h"},
+		{token.OPTIONALDOT, "?."},
+		{token.VARIABLE, "a"},
+		{token.OPTIONALINDEX, "?["},
+		{token.NUMBER, "0"},
+		{token.CSQUAREBR, "]"},
+		{token.COALESCE, "??"},
+		{token.VARIABLE, "d"},
+		{token.PIPE, "|>"},
+		{token.VARIABLE, "f"},
+		{token.INV, "|"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests{
+		tok := lexer.GetToken()
+
+		if tok.Type !=tt.expectedType{
+			t.Fatalf("tests[%d] - token type wrong, expected=%q, got=%q, expecedliteral=%q",i,tt.expectedType, tok.Type, tt.expectedLiteral)
+		}
+		if tok.Identifier !=tt.expectedLiteral{
+			t.Fatalf("tests[%d] - literal type wrong, expected=%q, got=%q",i,tt.expectedLiteral, tok.Identifier)
+		}
+	}
+}
+
+func TestIdentifierToken(t *testing.T){
+	input := `push_back starts_with x1 a_b_2 _ _x`
+
+	tests:=[]struct{
+		expectedType token.TokenType
+		expectedLiteral string
+	}{
+		{token.VARIABLE, "push_back"},
+		{token.VARIABLE, "starts_with"},
+		{token.VARIABLE, "x1"},
+		{token.VARIABLE, "a_b_2"},
+		{token.UNDERSCORE, "_"},
+		{token.UNDERSCORE, "_"},
+		{token.VARIABLE, "x"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests{
+		tok := lexer.GetToken()
+
+		if tok.Type !=tt.expectedType{
+			t.Fatalf("tests[%d] - token type wrong, expected=%q, got=%q, expecedliteral=%q",i,tt.expectedType, tok.Type, tt.expectedLiteral)
+		}
+		if tok.Identifier !=tt.expectedLiteral{
+			t.Fatalf("tests[%d] - literal type wrong, expected=%q, got=%q",i,tt.expectedLiteral, tok.Identifier)
+		}
+	}
+}
+
+func TestInterpolatedStringToken(t *testing.T){
+	input := `"plain" "Hello ${name}!" "${h["a"]} and ${ "${x}" }" "cost: $5 {x}" "open ${x"`
+
+	tests:=[]struct{
+		expectedType token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, "plain"},
+		{token.INTERPOLATED, "Hello ${name}!"},
+		{token.INTERPOLATED, `${h["a"]} and ${ "${x}" }`},
+		{token.STRING, "cost: $5 {x}"},
+		{token.INTERPOLATED, "open ${x\""},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tt := range tests{
+		tok := lexer.GetToken()
+
+		if tok.Type !=tt.expectedType{
+			t.Fatalf("tests[%d] - token type wrong, expected=%q, got=%q, expecedliteral=%q",i,tt.expectedType, tok.Type, tt.expectedLiteral)
+		}
+		if tok.Identifier !=tt.expectedLiteral{
+			t.Fatalf("tests[%d] - literal type wrong, expected=%q, got=%q",i,tt.expectedLiteral, tok.Identifier)
+		}
+	}
+}
+
+func TestSplitInterpolated(t *testing.T){
+	parts, ok := SplitInterpolated(`Hi ${name}, ${a + b}${c}!`)
+	if !ok{
+		t.Fatalf("the string should split")
+	}
+
+	expected := []InterpolationPart{
+		{Value: "Hi "},
+		{Value: "name", IsExpression: true},
+		{Value: ", "},
+		{Value: "a + b", IsExpression: true},
+		{Value: "c", IsExpression: true},
+		{Value: "!"},
+	}
+
+	if len(parts) != len(expected){
+		t.Fatalf("the number of parts not as expected=%d, got=%d", len(expected), len(parts))
+	}
+
+	for i, part := range parts{
+		if part != expected[i]{
+			t.Errorf("parts[%d] not as expected=%+v, got=%+v", i, expected[i], part)
+		}
+	}
+
+	if _, ok := SplitInterpolated("broken ${x"); ok{
+		t.Errorf("an unclosed ${ should not split")
+	}
+}
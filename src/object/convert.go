@@ -0,0 +1,412 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+var (
+	objectType = reflect.TypeOf((*Object)(nil)).Elem()
+	runtimeType = reflect.TypeOf((*Runtime)(nil)).Elem()
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	anyType = reflect.TypeOf((*any)(nil)).Elem()
+)
+
+//the field tag naming the hash key a struct field maps to, `monkey:"-"` leaves the field out
+const fieldTag = "monkey"
+
+//converts a go value into an object. ints, strings and bools become their scalar objects, slices become arrays,
+//maps and structs become hashes and funcs become builtins converting their arguments and results the same way
+func FromGo(value any) (Object, error){
+	if value == nil{
+		return NULL, nil
+	}
+
+	if obj, ok := value.(Object); ok{
+		return obj, nil
+	}
+
+	return fromGoValue(reflect.ValueOf(value))
+}
+
+func fromGoValue(v reflect.Value) (Object, error){
+	if v.Type().Implements(objectType){
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil(){
+			return NULL, nil
+		}
+		return v.Interface().(Object), nil
+	}
+
+	switch v.Kind(){
+	case reflect.Bool:
+		if v.Bool(){
+			return TRUE, nil
+		}
+		return FALSE, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64{
+			return nil, fmt.Errorf("%d does not fit in an integer", v.Uint())
+		}
+		return &Integer{Value: int64(v.Uint())}, nil
+	case reflect.String:
+		return &String{Value: v.String()}, nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]Object, v.Len())
+		for i := range elements{
+			el, err := fromGoValue(v.Index(i))
+			if err != nil{
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			elements[i] = el
+		}
+		return &Array{Elements: elements}, nil
+	case reflect.Map:
+		return fromGoMap(v)
+	case reflect.Struct:
+		return fromGoStruct(v)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil(){
+			return NULL, nil
+		}
+		return fromGoValue(v.Elem())
+	case reflect.Func:
+		if v.IsNil(){
+			return NULL, nil
+		}
+		return fromGoFunc(v), nil
+	default:
+		return nil, fmt.Errorf("cannot convert go type %s", v.Type())
+	}
+}
+
+//go maps have no order, the keys are sorted so the hash comes out the same every time
+func fromGoMap(v reflect.Value) (Object, error){
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool{
+		return lessMapKey(keys[i], keys[j])
+	})
+
+	hash := NewHash()
+	for _, key := range keys{
+		keyObj, err := fromGoValue(key)
+		if err != nil{
+			return nil, fmt.Errorf("map key: %w", err)
+		}
+
+		if _, ok := HashKeyOf(keyObj); !ok{
+			return nil, fmt.Errorf("map key of type %s is not usable as a hash key", key.Type())
+		}
+
+		value, err := fromGoValue(v.MapIndex(key))
+		if err != nil{
+			return nil, fmt.Errorf("map value for %s: %w", keyObj.Inspect(), err)
+		}
+
+		hash.Set(keyObj, value)
+	}
+
+	return hash, nil
+}
+
+func lessMapKey(a, b reflect.Value) bool{
+	switch a.Kind(){
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
+func fromGoStruct(v reflect.Value) (Object, error){
+	hash := NewHash()
+	for i := 0; i < v.NumField(); i++{
+		field := v.Type().Field(i)
+		name, ok := fieldName(field)
+		if !ok{
+			continue
+		}
+
+		value, err := fromGoValue(v.Field(i))
+		if err != nil{
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		hash.Set(&String{Value: name}, value)
+	}
+
+	return hash, nil
+}
+
+//the hash key for a struct field, the tag if there is one and the field name otherwise
+func fieldName(field reflect.StructField) (string, bool){
+	if !field.IsExported(){
+		return "", false
+	}
+
+	name := field.Tag.Get(fieldTag)
+	switch name{
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
+//the builtin converts the arguments into the parameter types, a leading Runtime parameter receives the runtime
+//and a trailing error result turns into an error object
+func fromGoFunc(fn reflect.Value) *Builtin{
+	fnType := fn.Type()
+
+	return &Builtin{Fn: func(rt Runtime, args ...Object) Object{
+		var in []reflect.Value
+		params := fnType.NumIn()
+		first := 0
+		if params > 0 && fnType.In(0) == runtimeType{
+			in = append(in, reflect.ValueOf(&rt).Elem())
+			first = 1
+		}
+
+		fixed := params - first
+		if fnType.IsVariadic(){
+			fixed--
+			if len(args) < fixed{
+				return &Error{Message: fmt.Sprintf("wrong number of args, expected at least %d, got=%d", fixed, len(args))}
+			}
+		}else if len(args) != fixed{
+			return &Error{Message: fmt.Sprintf("wrong number of args, expected=%d, got=%d", fixed, len(args))}
+		}
+
+		for i, arg := range args{
+			paramType := fnType.In(params-1)
+			if i < fixed{
+				paramType = fnType.In(first+i)
+			}else{
+				paramType = paramType.Elem()
+			}
+
+			param := reflect.New(paramType)
+			if err := ToGo(arg, param.Interface()); err != nil{
+				return &Error{Message: fmt.Sprintf("argument %d: %s", i+1, err)}
+			}
+			in = append(in, param.Elem())
+		}
+
+		return fromGoResults(fn.Call(in))
+	}}
+}
+
+func fromGoResults(results []reflect.Value) Object{
+	if len(results) > 0 && results[len(results)-1].Type() == errorType{
+		if err := results[len(results)-1]; !err.IsNil(){
+			return &Error{Message: err.Interface().(error).Error()}
+		}
+		results = results[:len(results)-1]
+	}
+
+	switch len(results){
+	case 0:
+		return NULL
+	case 1:
+		obj, err := fromGoValue(results[0])
+		if err != nil{
+			return &Error{Message: fmt.Sprintf("result: %s", err)}
+		}
+		return obj
+	default:
+		//several results come back as an array of them
+		elements := make([]Object, len(results))
+		for i, result := range results{
+			obj, err := fromGoValue(result)
+			if err != nil{
+				return &Error{Message: fmt.Sprintf("result %d: %s", i+1, err)}
+			}
+			elements[i] = obj
+		}
+		return &Array{Elements: elements}
+	}
+}
+
+//converts an object into the go value target points to, the reverse of FromGo. an `any` target gets int64,
+//string, bool, nil, []any or map[string]any depending on the object
+func ToGo(obj Object, target any) error{
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil(){
+		return fmt.Errorf("target has to be a non nil pointer, got %T", target)
+	}
+
+	return toGoValue(obj, v.Elem())
+}
+
+func toGoValue(obj Object, v reflect.Value) error{
+	//the target can hold the object itself, e.g. an Object or *Function parameter
+	if reflect.TypeOf(obj).AssignableTo(v.Type()) && v.Type() != anyType{
+		v.Set(reflect.ValueOf(obj))
+		return nil
+	}
+
+	if obj.Type() == NULL_VAL{
+		switch v.Kind(){
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+	}
+
+	switch v.Kind(){
+	case reflect.Interface:
+		if v.NumMethod() != 0{
+			break
+		}
+		value, err := toGoAny(obj)
+		if err != nil{
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Pointer:
+		ptr := reflect.New(v.Type().Elem())
+		if err := toGoValue(obj, ptr.Elem()); err != nil{
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Bool:
+		if b, ok := obj.(*Boolean); ok{
+			v.SetBool(b.Value)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := obj.(*Integer); ok{
+			if v.OverflowInt(integer.Value){
+				return fmt.Errorf("%d does not fit in %s", integer.Value, v.Type())
+			}
+			v.SetInt(integer.Value)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer, ok := obj.(*Integer); ok{
+			if integer.Value < 0 || v.OverflowUint(uint64(integer.Value)){
+				return fmt.Errorf("%d does not fit in %s", integer.Value, v.Type())
+			}
+			v.SetUint(uint64(integer.Value))
+			return nil
+		}
+	case reflect.String:
+		if str, ok := obj.(*String); ok{
+			v.SetString(str.Value)
+			return nil
+		}
+	case reflect.Slice:
+		if arr, ok := obj.(*Array); ok{
+			slice := reflect.MakeSlice(v.Type(), len(arr.Elements), len(arr.Elements))
+			for i, el := range arr.Elements{
+				if err := toGoValue(el, slice.Index(i)); err != nil{
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			v.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		if arr, ok := obj.(*Array); ok{
+			if len(arr.Elements) != v.Len(){
+				return fmt.Errorf("expected an array of %d elements, got %d", v.Len(), len(arr.Elements))
+			}
+			for i, el := range arr.Elements{
+				if err := toGoValue(el, v.Index(i)); err != nil{
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if hash, ok := obj.(*Hash); ok{
+			m := reflect.MakeMapWithSize(v.Type(), hash.Len())
+			for _, pair := range hash.Pairs(){
+				key := reflect.New(v.Type().Key()).Elem()
+				if err := toGoValue(pair.Key, key); err != nil{
+					return fmt.Errorf("key %s: %w", pair.Key.Inspect(), err)
+				}
+				value := reflect.New(v.Type().Elem()).Elem()
+				if err := toGoValue(pair.Value, value); err != nil{
+					return fmt.Errorf("value for %s: %w", pair.Key.Inspect(), err)
+				}
+				m.SetMapIndex(key, value)
+			}
+			v.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if hash, ok := obj.(*Hash); ok{
+			//keys without a matching field are ignored and fields without a key keep their zero value
+			for i := 0; i < v.NumField(); i++{
+				name, ok := fieldName(v.Type().Field(i))
+				if !ok{
+					continue
+				}
+				value, found := hash.Get(&String{Value: name})
+				if !found{
+					continue
+				}
+				if err := toGoValue(value, v.Field(i)); err != nil{
+					return fmt.Errorf("field %s: %w", name, err)
+				}
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot convert %s to go type %s", obj.Type(), v.Type())
+}
+
+func toGoAny(obj Object) (any, error){
+	switch obj := obj.(type){
+	case *Integer:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *Null:
+		return nil, nil
+	case *Array:
+		values := make([]any, len(obj.Elements))
+		for i, el := range obj.Elements{
+			value, err := toGoAny(el)
+			if err != nil{
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			values[i] = value
+		}
+		return values, nil
+	case *Hash:
+		values := make(map[string]any, obj.Len())
+		for _, pair := range obj.Pairs(){
+			key, ok := pair.Key.(*String)
+			if !ok{
+				return nil, fmt.Errorf("only hashes with string keys convert to map[string]any, got a %s key", pair.Key.Type())
+			}
+			value, err := toGoAny(pair.Value)
+			if err != nil{
+				return nil, fmt.Errorf("value for %s: %w", key.Value, err)
+			}
+			values[key.Value] = value
+		}
+		return values, nil
+	default:
+		//functions and builtins stay objects, they can only be called through the runtime
+		return obj, nil
+	}
+}
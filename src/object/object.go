@@ -2,8 +2,10 @@ package object
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"strings"
 
 	"github.com/singlaanish56/Interpreter-In-Go/ast"
@@ -20,6 +22,7 @@ const (
 	BUILTIN_OBJ="BUILTIN"
 	ARRAY_OBJ="ARRAY"
 	HASHPAIR_OBJ="HASHPAIR"
+	MODULE_OBJ="MODULE"
 )
 
 type HashKey struct{
@@ -36,6 +39,83 @@ type HashPair struct{
 	Value Object
 }
 
+//returns the hash key for any object usable as a hash key. arrays get a composite key
+//derived from their elements, so they are hashable as long as every element is
+func HashKeyOf(obj Object) (HashKey, bool){
+	switch obj := obj.(type){
+	case *Array:
+		h := fnv.New64a()
+		for _, e := range obj.Elements{
+			key, ok := HashKeyOf(e)
+			if !ok{
+				return HashKey{}, false
+			}
+
+			h.Write([]byte(key.Type))
+			binary.Write(h, binary.LittleEndian, key.Value)
+		}
+
+		return HashKey{Type: obj.Type(), Value: h.Sum64()}, true
+	case Hashable:
+		return obj.HashKey(), true
+	default:
+		return HashKey{}, false
+	}
+}
+
+//structural equality, objects that are equal always share the same HashKey.
+//functions are equal when they come from the same literal closed over the same environment
+func Equal(a, b Object) bool{
+	if a == b{
+		return true
+	}
+
+	switch a := a.(type){
+	case *Integer:
+		b, ok := b.(*Integer)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements){
+			return false
+		}
+
+		for i := range a.Elements{
+			if !Equal(a.Elements[i], b.Elements[i]){
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || a.Len() != b.Len(){
+			return false
+		}
+
+		for _, pair := range a.Pairs(){
+			value, ok := b.Get(pair.Key)
+			if !ok || !Equal(pair.Value, value){
+				return false
+			}
+		}
+		return true
+	case *Function:
+		b, ok := b.(*Function)
+		return ok && a.Body == b.Body && a.Env == b.Env
+	default:
+		return false
+	}
+}
+
 
 type ObjectType string
 
@@ -75,11 +155,17 @@ type String struct {
 func (s *String) Type() ObjectType { return STRING_VAL }
 func (s *String) Inspect() string { return fmt.Sprintf("%s",s.Value)}
 func (s *String) HashKey() HashKey{
+	return HashKey{Type: s.Type(), Value: StringHasher(s.Value)}
+}
+
+//hashes string keys, swappable so tests can force collisions
+var StringHasher = func(s string) uint64{
 	h := fnv.New64a()
-	h.Write([]byte(s.Value))
+	h.Write([]byte(s))
 
-	return HashKey{Type: s.Type(), Value: h.Sum64()}
+	return h.Sum64()
 }
+
 type Array struct{
 	Elements []Object
 }
@@ -100,17 +186,77 @@ func (a *Array) Inspect() string {
 	return out.String()
 }
 
+//keeps the pairs in insertion order. the index is keyed by HashKey, keys whose HashKey
+//collide share a bucket and are told apart by comparing the original keys. the zero value is an empty hash
 type Hash struct{
-	Pairs map[HashKey]HashPair
+	buckets map[HashKey][]int
+	pairs []HashPair
 }
 
+func NewHash() *Hash{
+	return &Hash{buckets: make(map[HashKey][]int)}
+}
+
+//adds or replaces the pair, a replaced key keeps its original position. returns false if the key is not hashable
+func (hm *Hash) Set(key Object, value Object) bool{
+	hashKey, ok := HashKeyOf(key)
+	if !ok{
+		return false
+	}
+
+	if idx, found := hm.find(hashKey, key); found{
+		hm.pairs[idx].Value = value
+		return true
+	}
+
+	if hm.buckets == nil{
+		hm.buckets = make(map[HashKey][]int)
+	}
+	hm.buckets[hashKey] = append(hm.buckets[hashKey], len(hm.pairs))
+	hm.pairs = append(hm.pairs, HashPair{Key: key, Value: value})
+	return true
+}
+
+func (hm *Hash) Get(key Object) (Object, bool){
+	hashKey, ok := HashKeyOf(key)
+	if !ok{
+		return nil, false
+	}
+
+	idx, found := hm.find(hashKey, key)
+	if !found{
+		return nil, false
+	}
+
+	return hm.pairs[idx].Value, true
+}
+
+func (hm *Hash) find(hashKey HashKey, key Object) (int, bool){
+	for _, idx := range hm.buckets[hashKey]{
+		if Equal(hm.pairs[idx].Key, key){
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+//the pairs in insertion order
+func (hm *Hash) Pairs() []HashPair{
+	pairs := make([]HashPair, len(hm.pairs))
+	copy(pairs, hm.pairs)
+
+	return pairs
+}
+
+func (hm *Hash) Len() int { return len(hm.pairs) }
 
 func (hm *Hash) Type() ObjectType { return HASHPAIR_OBJ }
 func (hm *Hash) Inspect() string {
 	var out bytes.Buffer
 
 	elements :=[]string{}
-	for _, p := range hm.Pairs{
+	for _, p := range hm.Pairs(){
 		elements = append(elements, p.Key.Inspect()+":"+p.Value.Inspect())
 	}
 
@@ -126,6 +272,13 @@ type Null struct{}
 func (b *Null) Type() ObjectType { return NULL_VAL }
 func (b *Null) Inspect() string { return "null"}
 
+//the evaluator tells booleans and null apart by identity, so everything producing them has to reuse these
+var (
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
+	NULL  = &Null{}
+)
+
 
 type ReturnValue struct{
 	Value Object
@@ -136,10 +289,47 @@ func (rv *ReturnValue) Inspect() string { return rv.Value.Inspect()}
 
 type Error struct{
 	Message string
+	Trace []string // names of the functions the error unwound through, innermost first
+	Cause error // the go error behind it when the host stopped the evaluation, e.g. context.Canceled
+	lastFrame string
+	repeats int // how often lastFrame was added in a row
+	omitted int // frames past maxTraceFrames, only counted
+}
+
+//the most lines a trace keeps, frames beyond it are only counted
+const maxTraceFrames = 64
+
+//adds a frame the error unwinds through. the same frame added in a row, e.g. by recursion, collapses
+//into one `f (xN)` line and frames past maxTraceFrames into a count, so a deep stack keeps the trace short
+func (e *Error) AddFrame(frame string){
+	last := len(e.Trace)-1
+	switch{
+	case e.omitted > 0:
+		e.omitted++
+		e.Trace[last] = fmt.Sprintf("... %d more", e.omitted)
+	case last >= 0 && frame == e.lastFrame:
+		e.repeats++
+		e.Trace[last] = fmt.Sprintf("%s (x%d)", frame, e.repeats)
+	case len(e.Trace) >= maxTraceFrames:
+		e.omitted = 1
+		e.Trace = append(e.Trace, "... 1 more")
+	default:
+		e.lastFrame, e.repeats = frame, 1
+		e.Trace = append(e.Trace, frame)
+	}
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string { return e.Message}
+func (e *Error) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString(e.Message)
+	for _, frame := range e.Trace{
+		out.WriteString("\n\tat "+frame)
+	}
+
+	return out.String()
+}
 
 
 type Environment struct{
@@ -174,7 +364,12 @@ func (e *Environment) Set(name string , obj Object) Object{
 
 
 type Function struct{
+	Name string
+	//the let binding of an anonymous function, shown in traces but not in Inspect
+	BoundName string
 	Params []*ast.Variable
+	Defaults map[string]ast.Expression
+	Rest *ast.Variable
 	Body *ast.BlockStatement
 	Env *Environment	
 }
@@ -183,13 +378,12 @@ func (f *Function) Type() ObjectType { return FUNCTION}
 func (f *Function) Inspect() string{
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p:= range f.Params{
-		params = append(params, p.String())
+	out.WriteString("fn")
+	if f.Name != ""{
+		out.WriteString(" "+f.Name)
 	}
-
-	out.WriteString("fn(")
-	out.WriteString(strings.Join(params,","))
+	out.WriteString("(")
+	out.WriteString(ast.ParametersString(f.Params, f.Defaults, f.Rest))
 	out.WriteString("){\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("}\n")
@@ -197,12 +391,45 @@ func (f *Function) Inspect() string{
 	return out.String()
 }
 
+//name used for the function in error traces
+func (f *Function) DisplayName() string{
+	switch{
+	case f.Name != "":
+		return f.Name
+	case f.BoundName != "":
+		return f.BoundName
+	default:
+		return "<anonymous>"
+	}
+}
 
-type BuiltinFunction func(args ...Object) Object
+
+//gives builtins a way back into the evaluator, e.g. to call a user function passed as an argument
+//or to reach the streams the script reads from and prints to
+type Runtime interface{
+	Call(fn Object, args ...Object) Object
+	Output() io.Writer
+	//returns the next line without its line ending, io.EOF once the input is exhausted
+	ReadLine() (string, error)
+	//checks that about size more bytes still fit the allocation budget, builtins call it before building a big result
+	//so the limit stops the allocation itself. the returned error is meant to be handed back as the result
+	Reserve(size int64) *Error
+}
+
+type BuiltinFunction func(rt Runtime, args ...Object) Object
 
 type Builtin struct{
 	Fn BuiltinFunction
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ}
-func (b *Builtin) Inspect() string { return "builtin function"}
\ No newline at end of file
+func (b *Builtin) Inspect() string { return "builtin function"}
+
+//an imported file, only the bindings it exported are reachable
+type Module struct{
+	Name string
+	Exports map[string]Object
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ}
+func (m *Module) Inspect() string { return "module "+m.Name}
@@ -1,7 +1,11 @@
 package object
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/singlaanish56/Interpreter-In-Go/ast"
 )
 
 func TestHashKey(t *testing.T){
@@ -16,4 +20,290 @@ func TestHashKey(t *testing.T){
 	if hello1.HashKey() == dif1.HashKey(){
 		t.Errorf("different strings cant have the same hashkey , str1=%s, str2=%s", hello1.Value, dif1.Value)
 	}
-}
\ No newline at end of file
+}
+
+func TestHashInsertionOrder(t *testing.T){
+	hash := NewHash()
+	keys := []Object{&String{Value: "z"}, &Integer{Value: 1}, &Boolean{Value: true}, &String{Value: "a"}}
+
+	for i, k := range keys{
+		hash.Set(k, &Integer{Value: int64(i)})
+	}
+	hash.Set(&String{Value: "z"}, &Integer{Value: 99})
+
+	if hash.Len() != len(keys){
+		t.Fatalf("the hash length not as expected=%d, got=%d", len(keys), hash.Len())
+	}
+
+	for i, pair := range hash.Pairs(){
+		if pair.Key.Inspect() != keys[i].Inspect(){
+			t.Errorf("pair %d out of order, expected=%s, got=%s", i, keys[i].Inspect(), pair.Key.Inspect())
+		}
+	}
+
+	if hash.Inspect() != "{z:99,1:1,true:2,a:3}"{
+		t.Errorf("the hash inspect not as expected, got=%s", hash.Inspect())
+	}
+
+	if hash.Set(&Hash{}, &Integer{Value: 1}){
+		t.Errorf("a hash key should not be accepted")
+	}
+
+	zero := &Hash{}
+	if _, ok := zero.Get(&String{Value: "a"}); ok || zero.Len() != 0{
+		t.Errorf("the zero hash should be empty")
+	}
+	if !zero.Set(&String{Value: "a"}, &Integer{Value: 1}) || zero.Inspect() != "{a:1}"{
+		t.Errorf("the zero hash should be usable, got=%s", zero.Inspect())
+	}
+}
+
+func TestEqual(t *testing.T){
+	fn := &Function{Body: &ast.BlockStatement{}}
+	hash1 := NewHash()
+	hash1.Set(&String{Value: "a"}, &Integer{Value: 1})
+	hash1.Set(&String{Value: "b"}, &Array{Elements: []Object{&Integer{Value: 2}}})
+	hash2 := NewHash()
+	hash2.Set(&String{Value: "b"}, &Array{Elements: []Object{&Integer{Value: 2}}})
+	hash2.Set(&String{Value: "a"}, &Integer{Value: 1})
+
+	tests := []struct{
+		left Object
+		right Object
+		expected bool
+	}{
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &Boolean{Value: true}, false},
+		{&String{Value: "a"}, &String{Value: "a"}, true},
+		{&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "x"}}}, &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "x"}}}, true},
+		{&Array{Elements: []Object{&Integer{Value: 1}}}, &Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}, false},
+		{&Array{Elements: []Object{}}, &Array{Elements: []Object{}}, true},
+		{hash1, hash2, true},
+		{hash1, NewHash(), false},
+		{fn, fn, true},
+		{fn, &Function{Body: &ast.BlockStatement{}}, false},
+		{&Null{}, &Null{}, true},
+	}
+
+	for i, tt := range tests{
+		if Equal(tt.left, tt.right) != tt.expected{
+			t.Errorf("tests[%d] - equal(%s, %s) not as expected=%t", i, tt.left.Inspect(), tt.right.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestArrayHashKey(t *testing.T){
+	arr1 := &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "two"}}}
+	arr2 := &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "two"}}}
+	arr3 := &Array{Elements: []Object{&String{Value: "two"}, &Integer{Value: 1}}}
+	nested := &Array{Elements: []Object{arr1}}
+
+	key1, ok1 := HashKeyOf(arr1)
+	key2, ok2 := HashKeyOf(arr2)
+	key3, _ := HashKeyOf(arr3)
+	if !ok1 || !ok2{
+		t.Fatalf("arrays of hashable values should be hashable")
+	}
+
+	if key1 != key2{
+		t.Errorf("equal arrays should have the same hashkey")
+	}
+
+	if key1 == key3{
+		t.Errorf("arrays with different order should have different hashkeys")
+	}
+
+	if _, ok := HashKeyOf(nested); !ok{
+		t.Errorf("nested arrays of hashable values should be hashable")
+	}
+
+	if _, ok := HashKeyOf(&Array{Elements: []Object{&Function{}}}); ok{
+		t.Errorf("an array holding a function should not be hashable")
+	}
+}
+
+func TestHashCollisions(t *testing.T){
+	original := StringHasher
+	StringHasher = func(string) uint64 { return 42 }
+	defer func(){ StringHasher = original }()
+
+	first := &String{Value: "first"}
+	second := &String{Value: "second"}
+	if first.HashKey() != second.HashKey(){
+		t.Fatalf("the hasher should force a collision")
+	}
+
+	hash := NewHash()
+	hash.Set(first, &Integer{Value: 1})
+	hash.Set(second, &Integer{Value: 2})
+	hash.Set(&String{Value: "first"}, &Integer{Value: 3})
+
+	if hash.Len() != 2{
+		t.Fatalf("colliding keys should not overwrite each other, expected len=2, got=%d", hash.Len())
+	}
+
+	tests := []struct{
+		key string
+		expected int64
+	}{
+		{"first", 3},
+		{"second", 2},
+	}
+
+	for _, tt := range tests{
+		value, ok := hash.Get(&String{Value: tt.key})
+		if !ok{
+			t.Errorf("the value for %s not found", tt.key)
+			continue
+		}
+
+		if value.(*Integer).Value != tt.expected{
+			t.Errorf("the value for %s not as expected=%d, got=%d", tt.key, tt.expected, value.(*Integer).Value)
+		}
+	}
+
+	if _, ok := hash.Get(&String{Value: "third"}); ok{
+		t.Errorf("a colliding key that was never set should not be found")
+	}
+
+	if hash.Inspect() != "{first:3,second:2}"{
+		t.Errorf("the hash inspect not as expected, got=%s", hash.Inspect())
+	}
+
+	other := NewHash()
+	other.Set(&String{Value: "second"}, &Integer{Value: 2})
+	other.Set(&String{Value: "first"}, &Integer{Value: 3})
+	if !Equal(hash, other){
+		t.Errorf("hashes with colliding keys should still compare equal")
+	}
+}
+
+func TestFromGo(t *testing.T){
+	type user struct{
+		Name string `monkey:"name"`
+		Age int
+		Tags []string `monkey:"tags"`
+		secret string
+		Skip bool `monkey:"-"`
+	}
+
+	tests := []struct{
+		input any
+		expected string
+	}{
+		{42, "42"},
+		{uint8(7), "7"},
+		{"hi", "hi"},
+		{true, "true"},
+		{nil, "null"},
+		{[]int{1, 2}, "[1,2]"},
+		{[]string(nil), "[]"},
+		{map[string]int{"b": 2, "a": 1}, "{a:1,b:2}"},
+		{user{Name: "ann", Age: 3, Tags: []string{"x"}, secret: "s"}, "{name:ann,Age:3,tags:[x]}"},
+		{&user{Name: "bob"}, "{name:bob,Age:0,tags:[]}"},
+	}
+
+	for _, tt := range tests{
+		obj, err := FromGo(tt.input)
+		if err != nil{
+			t.Errorf("unexpected error for %v: %s", tt.input, err)
+			continue
+		}
+
+		if obj.Inspect() != tt.expected{
+			t.Errorf("wrong conversion for %v, expected=%s, got=%s", tt.input, tt.expected, obj.Inspect())
+		}
+	}
+
+	if obj, _ := FromGo(false); obj != FALSE{
+		t.Errorf("booleans have to convert to the shared singletons")
+	}
+
+	if _, err := FromGo(1.5); err == nil{
+		t.Errorf("expected an error converting a float")
+	}
+}
+
+func TestToGo(t *testing.T){
+	type point struct{
+		X int `monkey:"x"`
+		Y int `monkey:"y"`
+	}
+
+	hash := NewHash()
+	hash.Set(&String{Value: "x"}, &Integer{Value: 1})
+	hash.Set(&String{Value: "y"}, &Integer{Value: 2})
+	hash.Set(&String{Value: "z"}, &Integer{Value: 3})
+
+	var p point
+	if err := ToGo(hash, &p); err != nil || p != (point{1, 2}){
+		t.Errorf("wrong struct conversion, got=%+v, %v", p, err)
+	}
+
+	var m map[string]int
+	if err := ToGo(hash, &m); err != nil || len(m) != 3 || m["z"] != 3{
+		t.Errorf("wrong map conversion, got=%v, %v", m, err)
+	}
+
+	var strs []string
+	arr := &Array{Elements: []Object{&String{Value: "a"}, &String{Value: "b"}}}
+	if err := ToGo(arr, &strs); err != nil || len(strs) != 2 || strs[1] != "b"{
+		t.Errorf("wrong slice conversion, got=%v, %v", strs, err)
+	}
+
+	var value any
+	if err := ToGo(arr, &value); err != nil || len(value.([]any)) != 2{
+		t.Errorf("wrong any conversion, got=%v, %v", value, err)
+	}
+
+	var small int8
+	if err := ToGo(&Integer{Value: 300}, &small); err == nil{
+		t.Errorf("expected an overflow error")
+	}
+
+	var str string
+	if err := ToGo(&Integer{Value: 1}, &str); err == nil || err.Error() != "cannot convert INTEGER to go type string"{
+		t.Errorf("wrong mismatch error, got=%v", err)
+	}
+}
+
+func TestFromGoFunc(t *testing.T){
+	obj, err := FromGo(func(name string, n int) ([]string, error){
+		if n < 0{
+			return nil, fmt.Errorf("n cannot be negative")
+		}
+		return []string{name, strings.Repeat("!", n)}, nil
+	})
+	if err != nil{
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fn := obj.(*Builtin).Fn
+	tests := []struct{
+		args []Object
+		expected string
+	}{
+		{[]Object{&String{Value: "hi"}, &Integer{Value: 2}}, "[hi,!!]"},
+		{[]Object{&String{Value: "hi"}, &Integer{Value: -1}}, "n cannot be negative"},
+		{[]Object{&String{Value: "hi"}}, "wrong number of args, expected=2, got=1"},
+		{[]Object{&Integer{Value: 1}, &Integer{Value: 1}}, "argument 1: cannot convert INTEGER to go type string"},
+	}
+
+	for _, tt := range tests{
+		result := fn(nil, tt.args...)
+		if result.Inspect() != tt.expected{
+			t.Errorf("wrong result, expected=%q, got=%q", tt.expected, result.Inspect())
+		}
+	}
+
+	sum, _ := FromGo(func(nums ...int) int{
+		total := 0
+		for _, n := range nums{
+			total += n
+		}
+		return total
+	})
+	if result := sum.(*Builtin).Fn(nil, &Integer{Value: 1}, &Integer{Value: 2}); result.Inspect() != "3"{
+		t.Errorf("wrong variadic result, got=%s", result.Inspect())
+	}
+}
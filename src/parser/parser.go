@@ -38,6 +38,7 @@ func New(lx *lexer.Lexer) *Parser{
 	parser.addPrefix(token.VARIABLE, parser.parseVariable)
 	parser.addPrefix(token.NUMBER, parser.parserIntegerLiteral)
 	parser.addPrefix(token.STRING, parser.parseStringExpression)
+	parser.addPrefix(token.INTERPOLATED, parser.parseInterpolatedString)
 	parser.addPrefix(token.EXCLAMATION, parser.parsePrefixExpression)
 	parser.addPrefix(token.MINUS, parser.parsePrefixExpression)
 	parser.addPrefix(token.PLUS, parser.parsePrefixExpression)
@@ -54,6 +55,7 @@ func New(lx *lexer.Lexer) *Parser{
 	parser.addPrefix(token.IF, parser.parseIfExpression)
 
 	parser.addPrefix(token.FUNCTION, parser.parseFunctionExpression)
+	parser.addPrefix(token.ELLIPSIS, parser.parseSpreadExpression)
 
 	parser.addInfix(token.PLUS, parser.parseInfixExpression)
 	parser.addInfix(token.MINUS, parser.parseInfixExpression)
@@ -66,6 +68,12 @@ func New(lx *lexer.Lexer) *Parser{
 	parser.addInfix(token.OSQAUREBR, parser.parseInfixIndexExpression)
 
 	parser.addInfix(token.OROUNDBR, parser.parseCallExpression)
+	parser.addInfix(token.DOT, parser.parseMemberExpression)
+	parser.addInfix(token.OPTIONALDOT, parser.parseMemberExpression)
+	parser.addInfix(token.OPTIONALINDEX, parser.parseInfixIndexExpression)
+	parser.addInfix(token.COALESCE, parser.parseInfixExpression)
+	parser.addInfix(token.QUESTION, parser.parseTernaryExpression)
+	parser.addInfix(token.PIPE, parser.parsePipeExpression)
 	return parser
 }
 
@@ -102,6 +110,15 @@ func (parser *Parser) parseStatement() ast.Statement{
 		return parser.parseLetStatement()
 	case token.RETURN:
 		return parser.parseReturnStatement()
+	case token.FUNCTION:
+		if parser.peekTokenIs(token.VARIABLE){
+			return parser.parseFunctionStatement()
+		}
+		return parser.parseExpressionStatment()
+	case token.IMPORT:
+		return parser.parseImportStatement()
+	case token.EXPORT:
+		return parser.parseExportStatement()
 	default:
 		return parser.parseExpressionStatment()
 	}
@@ -137,6 +154,29 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement{
 
 	st.Value = parser.parseExpression(LOWEST)
 
+	//let the anonymous function carry the name it is bound to
+	if fn, ok := st.Value.(*ast.FunctionExpression); ok && fn.Name == ""{
+		fn.BoundName = st.Variable.Value
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON){
+		parser.nextToken()
+	}
+
+	return st
+}
+
+func (parser *Parser) parseFunctionStatement() ast.Statement{
+	st := &ast.FunctionStatement{Token: parser.currToken}
+	st.Name = &ast.Variable{Token: parser.peekToken, Value: parser.peekToken.Identifier}
+
+	fn, ok := parser.parseFunctionExpression().(*ast.FunctionExpression)
+	if !ok{
+		return nil
+	}
+
+	st.Function = fn
+
 	if parser.peekTokenIs(token.SEMICOLON){
 		parser.nextToken()
 	}
@@ -144,6 +184,86 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement{
 	return st
 }
 
+func (parser *Parser) parseImportStatement() ast.Statement{
+	st := &ast.ImportStatement{Token: parser.currToken}
+
+	if parser.peekTokenIs(token.OCURLYBR){
+		parser.nextToken()
+		for !parser.peekTokenIs(token.CCURLYBR){
+			if !parser.checkPeekToken(token.VARIABLE){
+				return nil
+			}
+			st.Names = append(st.Names, &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier})
+
+			if !parser.peekTokenIs(token.COMMA){
+				break
+			}
+			parser.nextToken()
+		}
+
+		if !parser.checkPeekToken(token.CCURLYBR){
+			return nil
+		}
+
+		if len(st.Names) == 0{
+			parser.errorList = append(parser.errorList, fmt.Errorf("import needs at least one name between { and }"))
+			return nil
+		}
+
+		if !parser.checkPeekWord("from") || !parser.checkPeekToken(token.STRING){
+			return nil
+		}
+		st.Path = parser.currToken.Identifier
+	}else{
+		if !parser.checkPeekToken(token.STRING){
+			return nil
+		}
+		st.Path = parser.currToken.Identifier
+
+		if !parser.checkPeekWord("as") || !parser.checkPeekToken(token.VARIABLE){
+			return nil
+		}
+		st.Alias = &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON){
+		parser.nextToken()
+	}
+
+	return st
+}
+
+//only let and named fn declarations can be exported
+func (parser *Parser) parseExportStatement() ast.Statement{
+	st := &ast.ExportStatement{Token: parser.currToken}
+
+	switch{
+	case parser.peekTokenIs(token.LET):
+		parser.nextToken()
+		let := parser.parseLetStatement()
+		if let == nil{
+			return nil
+		}
+		st.Statement = let
+	case parser.peekTokenIs(token.FUNCTION):
+		parser.nextToken()
+		if !parser.peekTokenIs(token.VARIABLE){
+			parser.errorList = append(parser.errorList, fmt.Errorf("an exported function has to have a name"))
+			return nil
+		}
+		fn := parser.parseFunctionStatement()
+		if fn == nil{
+			return nil
+		}
+		st.Statement = fn
+	default:
+		parser.errorList = append(parser.errorList, fmt.Errorf("export has to be followed by let or fn, got %s", parser.peekToken.Type))
+		return nil
+	}
+
+	return st
+}
+
 func (parser *Parser) parseReturnStatement() *ast.ReturnStatement{
 	st := &ast.ReturnStatement{Token: parser.currToken}
 
@@ -180,7 +300,16 @@ func (parser *Parser) parseExpression(precendence int) ast.Expression{
 }
 
 func (parser *Parser) parseVariable() ast.Expression{
-	return &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+	variable := &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+
+	//`x => body`, the single parameter form of an arrow function
+	if parser.peekTokenIs(token.ARROW){
+		fnexp := &ast.FunctionExpression{Token: parser.currToken, Parameters: []*ast.Variable{variable}, Defaults: map[string]ast.Expression{}}
+		parser.nextToken()
+		return parser.parseArrowBody(fnexp)
+	}
+
+	return variable
 }
 
 func (parser *Parser) parserIntegerLiteral() ast.Expression{
@@ -200,6 +329,48 @@ func (parser *Parser) parseStringExpression() ast.Expression{
 	return strLiteral
 }
 
+//every `${...}` is parsed on its own and has to hold exactly one expression
+func (parser *Parser) parseInterpolatedString() ast.Expression{
+	exp := &ast.InterpolatedString{Token: parser.currToken}
+
+	parts, ok := lexer.SplitInterpolated(parser.currToken.Identifier)
+	if !ok{
+		parser.errorList = append(parser.errorList, fmt.Errorf("unterminated ${ in string %q", parser.currToken.Identifier))
+		return nil
+	}
+
+	for _, part := range parts{
+		if !part.IsExpression{
+			tok := token.Token{Type: token.STRING, Identifier: part.Value}
+			exp.Parts = append(exp.Parts, &ast.StringLiteral{Token: tok, Value: part.Value})
+			continue
+		}
+
+		inner := New(lexer.New(part.Value))
+		prog := inner.ParseProgram()
+		if len(inner.Errors()) > 0{
+			for _, err := range inner.Errors(){
+				parser.errorList = append(parser.errorList, fmt.Errorf("in ${%s}: %s", part.Value, err))
+			}
+			return nil
+		}
+
+		var st *ast.ExpressionStatement
+		if len(prog.Statements) == 1{
+			st, _ = prog.Statements[0].(*ast.ExpressionStatement)
+		}
+
+		if st == nil{
+			parser.errorList = append(parser.errorList, fmt.Errorf("${%s} has to hold exactly one expression", part.Value))
+			return nil
+		}
+
+		exp.Parts = append(exp.Parts, st.Expression)
+	}
+
+	return exp
+}
+
 func (parser *Parser) parseArrayExpression() ast.Expression{
 	arr := &ast.ArrayLiteral{Token: parser.currToken}
 
@@ -242,6 +413,14 @@ func (parser *Parser) parseBooleanExpression() ast.Expression{
 }
 
 func (parser *Parser) parseGroupedExpression() ast.Expression{
+	if parser.currTokenIs(token.OROUNDBR) && parser.startsArrowFunction(){
+		fnexp := &ast.FunctionExpression{Token: parser.currToken}
+		if !parser.parseFunctionParameters(fnexp) || !parser.checkPeekToken(token.ARROW){
+			return nil
+		}
+		return parser.parseArrowBody(fnexp)
+	}
+
 	parser.nextToken()
 
 	exp := parser.parseExpression(LOWEST)
@@ -253,6 +432,77 @@ func (parser *Parser) parseGroupedExpression() ast.Expression{
 	return exp
 }
 
+//the current token is an opening bracket, true when a parameter list followed by => starts here.
+//it scans a copy of the lexer so nothing is consumed, and stops at the first token no parameter list
+//has, so a grouped expression is given up on right away instead of being scanned to its end
+func (parser *Parser) startsArrowFunction() bool{
+	lx := *parser.lexer
+
+	for tok := parser.peekToken; ; tok = lx.GetToken(){
+		switch tok.Type{
+		case token.VARIABLE, token.COMMA, token.ELLIPSIS:
+		//only a default value has an = inside brackets
+		case token.EQUALTO:
+			return true
+		case token.CROUNDBR:
+			return lx.GetToken().Type == token.ARROW
+		default:
+			return false
+		}
+	}
+}
+
+//the current token is =>, a block is the function body as is and an expression becomes its only statement
+func (parser *Parser) parseArrowBody(fnexp *ast.FunctionExpression) ast.Expression{
+	if parser.peekTokenIs(token.OCURLYBR){
+		parser.nextToken()
+		fnexp.Body = parser.parseBlockStatement()
+		return fnexp
+	}
+
+	parser.nextToken()
+	st := &ast.ExpressionStatement{Token: parser.currToken}
+	st.Expression = parser.parseExpression(LOWEST)
+	fnexp.Body = &ast.BlockStatement{Token: parser.currToken, Statements: []ast.Statement{st}}
+
+	return fnexp
+}
+
+//`condition ? a : b`, the alternative is parsed one level below so a nested ternary groups to the right
+func (parser *Parser) parseTernaryExpression(condition ast.Expression) ast.Expression{
+	exp := &ast.TernaryExpression{Token: parser.currToken, Condition: condition}
+
+	parser.nextToken()
+	exp.Consequence = parser.parseExpression(LOWEST)
+
+	if !parser.checkPeekToken(token.COLON){
+		return nil
+	}
+
+	parser.nextToken()
+	exp.Alternative = parser.parseExpression(TERNARY-1)
+
+	return exp
+}
+
+//`x |> f(a)` is rewritten into the call `f(x, a)` and `x |> f` into `f(x)`, so the evaluator never sees the pipe
+func (parser *Parser) parsePipeExpression(left ast.Expression) ast.Expression{
+	tok := parser.currToken
+
+	parser.nextToken()
+	right := parser.parseExpression(PIPE)
+	if right == nil{
+		return nil
+	}
+
+	if call, ok := right.(*ast.CallExpression); ok{
+		call.Arguments = append([]ast.Expression{left}, call.Arguments...)
+		return call
+	}
+
+	return &ast.CallExpression{Token: tok, Function: right, Arguments: []ast.Expression{left}}
+}
+
 func (parser *Parser) parseIfExpression() ast.Expression{
 	exp := &ast.IfExpression{Token: parser.currToken}
 
@@ -290,11 +540,18 @@ func (parser *Parser) parseIfExpression() ast.Expression{
 func (parser *Parser) parseFunctionExpression() ast.Expression{
 	fnexp := &ast.FunctionExpression{Token :parser.currToken}
 
+	if parser.peekTokenIs(token.VARIABLE){
+		parser.nextToken()
+		fnexp.Name = parser.currToken.Identifier
+	}
+
 	if !parser.checkPeekToken(token.OROUNDBR){
 		return nil
 	}
 
-	fnexp.Parameters = parser.parseFunctionArguments()
+	if !parser.parseFunctionParameters(fnexp){
+		return nil
+	}
 
 	if !parser.checkPeekToken(token.OCURLYBR){
 		return nil
@@ -305,34 +562,54 @@ func (parser *Parser) parseFunctionExpression() ast.Expression{
 	return fnexp
 }
 
-func (parser *Parser) parseFunctionArguments() []*ast.Variable{
+//parses `(a, b = 10, ...rest)`, the current token is the opening bracket
+func (parser *Parser) parseFunctionParameters(fnexp *ast.FunctionExpression) bool{
 
-	params := []*ast.Variable{}
+	fnexp.Parameters = []*ast.Variable{}
+	fnexp.Defaults = make(map[string]ast.Expression)
 
 	if parser.peekTokenIs(token.CROUNDBR){
 		parser.nextToken()
-		return params
+		return true
 	}
 
-	parser.nextToken()
-
-	arg := &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
-	params = append(params, arg)
-
-	for parser.peekTokenIs(token.COMMA){
-		parser.nextToken()
+	for{
 		parser.nextToken()
 
-		arg = &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
-		params = append(params, arg)
-	}
+		if fnexp.Rest != nil{
+			parser.errorList = append(parser.errorList, fmt.Errorf("rest parameter %s has to be the last parameter", fnexp.Rest.Value))
+			return false
+		}
 
-	if !parser.checkPeekToken(token.CROUNDBR){
-		return nil
-	}
+		if parser.currTokenIs(token.ELLIPSIS){
+			if !parser.checkPeekToken(token.VARIABLE){
+				return false
+			}
+
+			fnexp.Rest = &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+		}else{
+			if !parser.currTokenIs(token.VARIABLE){
+				parser.errorList = append(parser.errorList, fmt.Errorf("expected a parameter name, got %s", parser.currToken.Type))
+				return false
+			}
+
+			param := &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+			fnexp.Parameters = append(fnexp.Parameters, param)
+
+			if parser.peekTokenIs(token.EQUALTO){
+				parser.nextToken()
+				parser.nextToken()
+				fnexp.Defaults[param.Value] = parser.parseExpression(LOWEST)
+			}
+		}
 
-	return params
+		if !parser.peekTokenIs(token.COMMA){
+			break
+		}
+		parser.nextToken()
+	}
 
+	return parser.checkPeekToken(token.CROUNDBR)
 }
 
 func (parser *Parser) parseBlockStatement() *ast.BlockStatement{
@@ -354,6 +631,16 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement{
 	return bexp
 }
 
+func (parser *Parser) parseSpreadExpression() ast.Expression{
+	exp := &ast.SpreadExpression{Token: parser.currToken}
+
+	parser.nextToken()
+
+	exp.Value = parser.parseExpression(PREFIX)
+
+	return exp
+}
+
 func (parser *Parser) parsePrefixExpression() ast.Expression{
 	exp := &ast.PrefixExpression{Token: parser.currToken, Operator: parser.currToken.Identifier}
 
@@ -364,18 +651,50 @@ func (parser *Parser) parsePrefixExpression() ast.Expression{
 	return exp
 }
 
+func (parser *Parser) parseMemberExpression(left ast.Expression) ast.Expression{
+	exp := &ast.MemberExpression{Token: parser.currToken, Left: left, Optional: parser.currTokenIs(token.OPTIONALDOT)}
+
+	if !parser.checkPeekToken(token.VARIABLE){
+		return nil
+	}
+	exp.Property = &ast.Variable{Token: parser.currToken, Value: parser.currToken.Identifier}
+
+	return exp
+}
+
+//parses both `a[i]` and the slice forms `a[i:j]`, `a[:j]`, `a[i:]` and `a[:]`
 func (parser *Parser) parseInfixIndexExpression(left ast.Expression) ast.Expression{
-	indexExp := &ast.IndexExpression{Token : parser.currToken, Left: left}
+	tok := parser.currToken
 
 	parser.nextToken()
 
-	indexExp.Index = parser.parseExpression(LOWEST)
+	var start ast.Expression
+	if !parser.currTokenIs(token.COLON){
+		start = parser.parseExpression(LOWEST)
+
+		if !parser.peekTokenIs(token.COLON){
+			if !parser.checkPeekToken(token.CSQUAREBR){
+				return nil
+			}
+
+			return &ast.IndexExpression{Token : tok, Left: left, Index: start, Optional: tok.Type == token.OPTIONALINDEX}
+		}
+
+		parser.nextToken()
+	}
+
+	sliceExp := &ast.SliceExpression{Token: tok, Left: left, Start: start, Optional: tok.Type == token.OPTIONALINDEX}
+
+	if !parser.peekTokenIs(token.CSQUAREBR){
+		parser.nextToken()
+		sliceExp.End = parser.parseExpression(LOWEST)
+	}
 
 	if !parser.checkPeekToken(token.CSQUAREBR){
 		return nil
 	}
 
-	return indexExp
+	return sliceExp
 }
 
 
@@ -405,7 +724,7 @@ func(parser *Parser) parseInfixExpression(left ast.Expression) ast.Expression{
 func(parser *Parser) parseHashMapExpression() ast.Expression{
 
 	hashExp := &ast.HashLiteral{Token: parser.currToken}
-	hashExp.Pairs = make(map[ast.Expression]ast.Expression)
+	hashExp.Pairs = []ast.HashLiteralPair{}
 
 	for !parser.peekTokenIs(token.CCURLYBR){
 		parser.nextToken()
@@ -419,7 +738,7 @@ func(parser *Parser) parseHashMapExpression() ast.Expression{
 		parser.nextToken()
 		value := parser.parseExpression(LOWEST)
 
-		hashExp.Pairs[key]=value
+		hashExp.Pairs = append(hashExp.Pairs, ast.HashLiteralPair{Key: key, Value: value})
 
 		if !parser.peekTokenIs(token.CCURLYBR) && !parser.checkPeekToken(token.COMMA){
 			return nil
@@ -445,6 +764,17 @@ func (parser *Parser) checkPeekToken(tokenType token.TokenType) bool{
 	return false
 }
 
+//as and from are only keywords inside an import, anywhere else they stay usable as variable names
+func (parser *Parser) checkPeekWord(word string) bool{
+	if parser.peekTokenIs(token.VARIABLE) && parser.peekToken.Identifier == word{
+		parser.nextToken()
+		return true
+	}
+
+	parser.errorList = append(parser.errorList, fmt.Errorf("expected next token to be %s, got %s", word, parser.peekToken.Identifier))
+	return false
+}
+
 func (parser *Parser) peekError(tokenType token.TokenType){
 	err := fmt.Errorf("expected next token to be %s, got %s", tokenType, parser.peekToken.Type)
 	parser.errorList = append(parser.errorList, err)
@@ -498,11 +828,20 @@ var precendences = map[token.TokenType] int{
 	token.DIVIDE: PRODUCT,
 	token.OROUNDBR: CALL,
 	token.OSQAUREBR: INDEX,
+	token.DOT: INDEX,
+	token.OPTIONALDOT: INDEX,
+	token.OPTIONALINDEX: INDEX,
+	token.COALESCE: COALESCE,
+	token.QUESTION: TERNARY,
+	token.PIPE: PIPE,
 }
 
 const (
 	_ int = iota
 	LOWEST
+	PIPE
+	TERNARY
+	COALESCE
 	EQUALS
 	LESSGREATER
 	SUM
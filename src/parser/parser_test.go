@@ -188,6 +188,49 @@ func TestStringExpression(t *testing.T){
 	}
 }
 
+func TestInterpolatedString(t *testing.T){
+	input := `"Hello ${name}, ${1 + 2}"`
+
+	l := lexer.New(input)
+	p := New(l)
+	prog := p.ParseProgram()
+	checkForErrors(p, t)
+
+	str, ok := prog.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InterpolatedString)
+	if !ok{
+		t.Fatalf("the expression not an interpolated string, got=%T", prog.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+
+	if len(str.Parts) != 4{
+		t.Fatalf("the number of parts not as expected=4, got=%d", len(str.Parts))
+	}
+
+	if lit, ok := str.Parts[0].(*ast.StringLiteral); !ok || lit.Value != "Hello "{
+		t.Errorf("the first part not as expected, got=%+v", str.Parts[0])
+	}
+
+	testIdentifier(t, str.Parts[1], "name")
+	testInfix(t, str.Parts[3], 1, "+", 2)
+
+	errorTests := []struct{
+		input string
+		expected string
+	}{
+		{`"${}"`, "${} has to hold exactly one expression"},
+		{`"${a; b}"`, "${a; b} has to hold exactly one expression"},
+		{`"${x"`, `unterminated ${ in string "${x\""`},
+	}
+
+	for _, tt := range errorTests{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0].Error() != tt.expected{
+			t.Errorf("parser errors for %s not as expected=%q, got=%v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
 func TestParseArrayLiteral(t *testing.T){
 	input :="[1, 2*2, 3+3]"
 
@@ -496,6 +539,167 @@ func TestFunctionParameters(t *testing.T){
 	}
 }
 
+func TestFunctionDefaultAndRestParameters(t *testing.T){
+	tests := []struct{
+		input string
+		expectedParams []string
+		expectedDefaults map[string]string
+		expectedRest string
+	}{
+		{"fn(a, b = 10){}", []string{"a","b"}, map[string]string{"b":"10"}, ""},
+		{"fn(a, b = a * 2, ...rest){}", []string{"a","b"}, map[string]string{"b":"(a*2)"}, "rest"},
+		{"fn(...args){}", []string{}, map[string]string{}, "args"},
+	}
+
+	for _, tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+		prog := p.ParseProgram()
+		checkForErrors(p,t)
+
+		fn, ok := prog.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionExpression)
+		if !ok{
+			t.Fatalf("the expression not a function, got=%T", prog.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+
+		if len(fn.Parameters) != len(tt.expectedParams){
+			t.Fatalf("the length of the parameters not as expected=%d, got=%d", len(tt.expectedParams), len(fn.Parameters))
+		}
+
+		for i, arg := range fn.Parameters{
+			testLiteral(t, arg, tt.expectedParams[i])
+		}
+
+		if len(fn.Defaults) != len(tt.expectedDefaults){
+			t.Errorf("the number of defaults not as expected=%d, got=%d", len(tt.expectedDefaults), len(fn.Defaults))
+		}
+
+		for name, expected := range tt.expectedDefaults{
+			def, ok := fn.Defaults[name]
+			if !ok{
+				t.Errorf("no default found for parameter %s", name)
+				continue
+			}
+
+			if def.String() != expected{
+				t.Errorf("the default for %s not as expected=%s, got=%s", name, expected, def.String())
+			}
+		}
+
+		if tt.expectedRest == ""{
+			if fn.Rest != nil{
+				t.Errorf("expected no rest parameter, got=%s", fn.Rest.Value)
+			}
+			continue
+		}
+
+		if fn.Rest == nil || fn.Rest.Value != tt.expectedRest{
+			t.Errorf("the rest parameter not as expected=%s, got=%v", tt.expectedRest, fn.Rest)
+		}
+	}
+}
+
+func TestFunctionParameterErrors(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"fn(...rest, a){}", "rest parameter rest has to be the last parameter"},
+		{"fn(1){}", "expected a parameter name, got INT"},
+	}
+
+	for _, tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0{
+			t.Errorf("expected parser errors for %q, got none", tt.input)
+			continue
+		}
+
+		if p.Errors()[0].Error() != tt.expected{
+			t.Errorf("wrong parser error, expected=%q, got=%q", tt.expected, p.Errors()[0].Error())
+		}
+	}
+}
+
+func TestSpreadExpression(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"f(...arr)", "f(...arr)"},
+		{"f(1, ...rest(arr))", "f(1,...rest(arr))"},
+		{"[0, ...arr, 4]", "[0,...arr,4]"},
+	}
+
+	for _, tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+		prog := p.ParseProgram()
+		checkForErrors(p,t)
+
+		if prog.String() != tt.expected{
+			t.Errorf("the program not as expected=%q, got=%q", tt.expected, prog.String())
+		}
+	}
+}
+
+func TestFunctionStatement(t *testing.T){
+	input := `fn add(x, y){x+y;} let sub = fn(x, y){x-y;}; let mul = fn times(x, y){x*y;};`
+
+	l := lexer.New(input)
+	p := New(l)
+	prog := p.ParseProgram()
+	checkForErrors(p,t)
+
+	if len(prog.Statements) != 3{
+		t.Fatalf("the number of statements not as expected=3, got=%d", len(prog.Statements))
+	}
+
+	st, ok := prog.Statements[0].(*ast.FunctionStatement)
+	if !ok{
+		t.Fatalf("the statement not a function declaration, got=%T", prog.Statements[0])
+	}
+
+	if !testIdentifier(t, st.Name, "add"){
+		return
+	}
+
+	if st.Function.Name != "add"{
+		t.Errorf("the function name not as expected=add, got=%q", st.Function.Name)
+	}
+
+	if st.String() != "fn add(x,y)(x+y)"{
+		t.Errorf("the function string not as expected, got=%q", st.String())
+	}
+
+	expected := []struct{
+		name string
+		boundName string
+		str string
+	}{
+		{"", "sub", "let sub = fn(x,y)(x-y);"},
+		{"times", "", "let mul = fn times(x,y)(x*y);"},
+	}
+	for i, tt := range expected{
+		let := prog.Statements[i+1].(*ast.LetStatement)
+		fn, ok := let.Value.(*ast.FunctionExpression)
+		if !ok{
+			t.Fatalf("the let value not a function, got=%T", let.Value)
+		}
+
+		if fn.Name != tt.name || fn.BoundName != tt.boundName{
+			t.Errorf("the function names not as expected=%q/%q, got=%q/%q", tt.name, tt.boundName, fn.Name, fn.BoundName)
+		}
+
+		if let.String() != tt.str{
+			t.Errorf("the let string not as expected=%q, got=%q", tt.str, let.String())
+		}
+	}
+}
+
 func TestCallExpressions(t *testing.T){
 	input := `add(1, 2*3, 4+5)`
 
@@ -570,6 +774,44 @@ func TestParseIndexExpressopn(t *testing.T){
 
 }
 
+func TestParseSliceExpression(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{"arr[1:3]", "(arr[1:3])"},
+		{"arr[:n]", "(arr[:n])"},
+		{"arr[-2:]", "(arr[(-2):])"},
+		{"arr[:]", "(arr[:])"},
+		{"arr[1+1:len(arr)]", "(arr[(1+1):len(arr)])"},
+		{"arr[1]", "(arr[1])"},
+	}
+
+	for _, tt := range tests{
+		l := lexer.New(tt.input)
+		p := New(l)
+		prog := p.ParseProgram()
+		checkForErrors(p,t)
+
+		if prog.String() != tt.expected{
+			t.Errorf("the program not as expected=%q, got=%q", tt.expected, prog.String())
+		}
+	}
+
+	l := lexer.New("arr[1:3]")
+	p := New(l)
+	prog := p.ParseProgram()
+
+	exp, ok := prog.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression)
+	if !ok{
+		t.Fatalf("the expression type not as expected, got=%T", prog.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+
+	testIdentifier(t, exp.Left, "arr")
+	testLiteral(t, exp.Start, 1)
+	testLiteral(t, exp.End, 3)
+}
+
 func TestParsingHashLiteral(t *testing.T){
 	input := `{"one":1,"second":2, "third":3}`
 
@@ -600,21 +842,170 @@ func TestParsingHashLiteral(t *testing.T){
 		return
 	}
 
-	expected := map[string]int{
-		"one":1,
-		"second":2,
-		"third":3,
+	expected := []struct{
+		key string
+		value int64
+	}{
+		{"one",1},
+		{"second",2},
+		{"third",3},
 	}
 
-	for key, value:= range hash.Pairs{
-		literal, ok := key.(*ast.StringLiteral)
+	for i, pair:= range hash.Pairs{
+		literal, ok := pair.Key.(*ast.StringLiteral)
 		if !ok{
-			t.Errorf("key is not a string literal, got=%T", literal)
+			t.Errorf("key is not a string literal, got=%T", pair.Key)
+			continue
+		}
+
+		if literal.String() != expected[i].key{
+			t.Errorf("the key is out of source order, expected=%s, got=%s", expected[i].key, literal.String())
+		}
+
+		testIntegerLiteral(t, pair.Value, expected[i].value)
+	}
+
+	if hash.String() != "{one:1,second:2,third:3}"{
+		t.Errorf("the hash string not as expected, got=%q", hash.String())
+	}
+}
+
+func TestParseModules(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`import "lib/math.monkey" as m;`, `import "lib/math.monkey" as m;`},
+		{`import { a, b } from "lib.monkey"`, `import { a, b } from "lib.monkey";`},
+		{`export let x = 5;`, `export let x = 5;`},
+		{`export fn add(a, b){ a + b }`, `export fn add(a,b)(a+b)`},
+		{`m.add(1, 2) * m.x`, `((m.add)(1,2)*(m.x))`},
+		{`let from = 1; let as = from;`, `let from = 1;let as = from;`},
+		{`s.trim().upper()`, `((s.trim)().upper)()`},
+		{`h.a.b[0]`, `(((h.a).b)[0])`},
+		{`h?.a?[0]?[1:]`, `(((h?.a)?[0])?[1:])`},
+		{`a ?? b == c`, `(a??(b==c))`},
+		{`a ?? b ?? c`, `((a??b)??c)`},
+	}
+
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		prog := p.ParseProgram()
+		checkForErrors(p,t)
+
+		if prog.String() != tt.expected{
+			t.Errorf("the program not as expected=%q, got=%q", tt.expected, prog.String())
 		}
+	}
+
+	errorTests := []struct{
+		input string
+		expected string
+	}{
+		{`import "lib.monkey";`, "expected next token to be as, got ;"},
+		{`import { } from "lib.monkey";`, "import needs at least one name between { and }"},
+		{`import { a } "lib.monkey";`, "expected next token to be from, got lib.monkey"},
+		{`export 5;`, "export has to be followed by let or fn, got INT"},
+		{`export fn(){};`, "an exported function has to have a name"},
+	}
 
-		expectedValue := expected[literal.String()]
+	for _, tt := range errorTests{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0].Error() != tt.expected{
+			t.Errorf("wrong parser errors for %q, expected first=%q, got=%v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
+func TestParseTernaryAndArrows(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`a > b ? a : b`, `((a>b) ? a : b)`},
+		{`a ? b : c ? d : e`, `(a ? b : (c ? d : e))`},
+		{`a ? b ? c : d : e`, `(a ? (b ? c : d) : e)`},
+		{`a ?? b ? c : d`, `((a??b) ? c : d)`},
+		{`x => x * 2`, `fn(x)(x*2)`},
+		{`(x, y) => x + y`, `fn(x,y)(x+y)`},
+		{`() => 1`, `fn()1`},
+		{`(x) => { let y = x; y }`, `fn(x)let y = x;y`},
+		{`map(a, x => x + 1)`, `map(a,fn(x)(x+1))`},
+		{`(a + b) * c`, `((a+b)*c)`},
+		{`(f(x)) => 1`, ``},
+		{`(a, b = f(1)) => a + b`, `fn(a,b=f(1))(a+b)`},
+		{`((((a)))) + (b)`, `(a+b)`},
+		{`x => y => x + y`, `fn(x)fn(y)(x+y)`},
+		{`ok ? x => 1 : x => 2`, `(ok ? fn(x)1 : fn(x)2)`},
+	}
+
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		prog := p.ParseProgram()
+		if tt.expected == ""{
+			if len(p.Errors()) == 0{
+				t.Errorf("expected parser errors for %q", tt.input)
+			}
+			continue
+		}
+		checkForErrors(p,t)
+
+		if prog.String() != tt.expected{
+			t.Errorf("the program not as expected=%q, got=%q", tt.expected, prog.String())
+		}
+	}
+
+	errorTests := []struct{
+		input string
+		expected string
+	}{
+		{`a ? b`, "expected next token to be :, got EOF"},
+		{`(x, y) =>`, "no matching func found for the token EOF"},
+	}
+
+	for _, tt := range errorTests{
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0].Error() != tt.expected{
+			t.Errorf("wrong parser errors for %q, expected first=%q, got=%v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
+func TestParsePipeline(t *testing.T){
+	tests := []struct{
+		input string
+		expected string
+	}{
+		{`xs |> map(f)`, `map(xs,f)`},
+		{`xs |> map(f) |> filter(g)`, `filter(map(xs,f),g)`},
+		{`x |> f`, `f(x)`},
+		{`x |> f |> g(1)`, `g(f(x),1)`},
+		{`a + b |> f(c * d)`, `f((a+b),(c*d))`},
+		{`s |> s.upper()`, `(s.upper)(s)`},
+		{`x |> y => y * 2`, `fn(y)(y*2)(x)`},
+		{`ok ? a : b |> f`, `f((ok ? a : b))`},
+		{`a ?? b |> f`, `f((a??b))`},
+		{`let y = x |> f;`, `let y = f(x);`},
+	}
+
+	for _, tt := range tests{
+		p := New(lexer.New(tt.input))
+		prog := p.ParseProgram()
+		checkForErrors(p,t)
+
+		if prog.String() != tt.expected{
+			t.Errorf("the program not as expected=%q, got=%q", tt.expected, prog.String())
+		}
+	}
 
-		testIntegerLiteral(t, value, int64(expectedValue))
+	p := New(lexer.New(`x |>`))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 || p.Errors()[0].Error() != "no matching func found for the token EOF"{
+		t.Errorf("wrong parser errors for a dangling pipe, got=%v", p.Errors())
 	}
 }
 
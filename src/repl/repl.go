@@ -17,16 +17,27 @@ const PROMPT = ">> "
 
 func Start(in io.Reader,out io.Writer) {
 
-	scanner := bufio.NewScanner(in)
+	//the reader is shared with the context so input() and the prompt take turns on the same stream
+	reader := bufio.NewReader(in)
+	ctx := evaluation.NewContext(out, reader)
+	//whoever starts the repl is at the terminal themselves, so unlike an embedding host it allows everything
+	ctx.Enable(evaluation.AllCapabilities...)
+
+	//one environment for the session, so what one line defines the next can use
+	e := object.NewEnv()
+	if prelude, err := ctx.Prelude(); err != nil{
+		io.WriteString(out, "could not load the stdlib: "+err.Inspect()+"\n")
+	}else{
+		e = object.NewEnclosedEnvironment(prelude)
+	}
 
 	for {
-		fmt.Print(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned{
+		fmt.Fprint(out, PROMPT)
+		input, err := ctx.ReadLine()
+		if err != nil{
 			return
 		}
 
-		input := scanner.Text()
 		lexer := lexer.New(input)
 		parser := parser.New(lexer)	
 		
@@ -35,8 +46,7 @@ func Start(in io.Reader,out io.Writer) {
 			printParserErrors(out, parser.Errors())
 			continue
 		}
-		e := object.NewEnv()
-		obj := evaluation.Eval(program, e)
+		obj := ctx.Eval(program, e)
 		if obj!=nil{
 			io.WriteString(out, obj.Inspect())
 			io.WriteString(out,"\n")	
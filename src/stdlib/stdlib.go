@@ -0,0 +1,37 @@
+//the standard library, written in the language itself and embedded in the binary
+package stdlib
+
+import (
+	"embed"
+	"sort"
+	"strings"
+)
+
+//go:embed *.monkey
+var files embed.FS
+
+//the prefix that imports the stdlib module by name instead of from a file, e.g. `import "std/strings" as s;`
+const ImportPrefix = "std/"
+
+//the source of the named module, the name leaves out the .monkey extension
+func Source(name string) (string, bool){
+	content, err := files.ReadFile(name + ".monkey")
+	if err != nil{
+		return "", false
+	}
+
+	return string(content), true
+}
+
+//every module name in a stable order
+func Names() []string{
+	entries, _ := files.ReadDir(".")
+
+	names := []string{}
+	for _, entry := range entries{
+		names = append(names, strings.TrimSuffix(entry.Name(), ".monkey"))
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,126 @@
+package stdlib_test
+
+import (
+	"testing"
+
+	"github.com/singlaanish56/Interpreter-In-Go/evaluation"
+	"github.com/singlaanish56/Interpreter-In-Go/lexer"
+	"github.com/singlaanish56/Interpreter-In-Go/object"
+	"github.com/singlaanish56/Interpreter-In-Go/parser"
+	"github.com/singlaanish56/Interpreter-In-Go/stdlib"
+)
+
+type stdlibTest struct{
+	input string
+	expected string
+}
+
+func TestFunctional(t *testing.T){
+	runStdlibTests(t, "functional", []stdlibTest{
+		{`identity(5)`, "5"},
+		{`compose(fn(x){ x + 1 }, fn(x){ x * 2 })(5)`, "11"},
+		{`compose()(5)`, "5"},
+		{`times(3, fn(i){ i * i })`, "[0,1,4]"},
+		{`find([1, 2, 3, 4], fn(x){ x > 2 })`, "3"},
+		{`find([1, 2], fn(x){ x > 2 })`, "null"},
+		{`count([1, 2, 3, 4], fn(x){ x > 1 })`, "3"},
+		{`take([1, 2, 3], 2)`, "[1,2]"},
+		{`take([1, 2, 3], 5)`, "[1,2,3]"},
+		{`drop([1, 2, 3], 2)`, "[3]"},
+		{`chunk([1, 2, 3, 4, 5], 2)`, "[[1,2],[3,4],[5]]"},
+		{`chunk([1, 2], 0)`, "[]"},
+		{`partition([1, 2, 3, 4], fn(x){ x > 2 })`, "[[3,4],[1,2]]"},
+		{`group_by(["apple", "avocado", "banana"], fn(s){ s[0] })`, "{a:[apple,avocado],b:[banana]}"},
+	})
+}
+
+func TestStrings(t *testing.T){
+	runStdlibTests(t, "strings", []stdlibTest{
+		{`pad_left("7", 3, "0")`, "007"},
+		{`pad_left("long", 2)`, "long"},
+		{`pad_left("x", 4, "ab")`, "abax"},
+		{`pad_right("ab", 4)`, "ab  "},
+		{`pad_right("ab", 5, "-")`, "ab---"},
+		{`capitalize("monkey")`, "Monkey"},
+		{`capitalize("")`, ""},
+		{`words("  the quick   fox ")`, "[the,quick,fox]"},
+		{`is_blank("   ")`, "true"},
+		{`is_blank(" a ")`, "false"},
+	})
+}
+
+func TestMath(t *testing.T){
+	runStdlibTests(t, "math", []stdlibTest{
+		{`mod(7, 3)`, "1"},
+		{`mod(-7, 3)`, "-1"},
+		{`is_even(4)`, "true"},
+		{`is_even(-3)`, "false"},
+		{`is_odd(3)`, "true"},
+		{`gcd(12, 18)`, "6"},
+		{`gcd(-4, 0)`, "4"},
+		{`lcm(4, 6)`, "12"},
+		{`lcm(0, 6)`, "0"},
+		{`factorial(5)`, "120"},
+		{`factorial(0)`, "1"},
+	})
+}
+
+func TestNames(t *testing.T){
+	names := stdlib.Names()
+	expected := []string{"functional", "math", "strings"}
+	if len(names) != len(expected){
+		t.Fatalf("wrong stdlib modules, expected=%v, got=%v", expected, names)
+	}
+
+	for i, name := range expected{
+		if names[i] != name{
+			t.Errorf("wrong stdlib module at %d, expected=%s, got=%s", i, name, names[i])
+		}
+	}
+
+	if _, ok := stdlib.Source("missing"); ok{
+		t.Errorf("expected no source for a missing module")
+	}
+}
+
+func TestImportMissingModule(t *testing.T){
+	eval := testEval(`import "std/missing" as m;`)
+	expected := "could not import std/missing: no stdlib module missing, have functional, math, strings"
+	if eval.Inspect() != expected{
+		t.Errorf("wrong error, expected=%q, got=%q", expected, eval.Inspect())
+	}
+}
+
+func TestPrelude(t *testing.T){
+	ctx := evaluation.NewContext(nil, nil)
+	prelude, err := ctx.Prelude()
+	if err != nil{
+		t.Fatalf("could not load the prelude: %s", err.Inspect())
+	}
+
+	env := object.NewEnclosedEnvironment(prelude)
+	program := parser.New(lexer.New(`let identity = fn(x){ 0 }; [pad_left("1", 2, "0"), identity(5)]`)).ParseProgram()
+
+	//bindings of the script shadow the stdlib
+	eval := ctx.Eval(program, env)
+	if eval.Inspect() != "[01,0]"{
+		t.Errorf("wrong result, expected=[01,0], got=%s", eval.Inspect())
+	}
+}
+
+//every input calls one function through the module imported by name, e.g. `pad_left(...)` runs as `lib.pad_left(...)`
+func runStdlibTests(t *testing.T, module string, tests []stdlibTest){
+	t.Helper()
+
+	for _, tt := range tests{
+		eval := testEval(`import "std/` + module + `" as lib; lib.` + tt.input)
+		if eval.Inspect() != tt.expected{
+			t.Errorf("wrong result for %s, expected=%q, got=%q", tt.input, tt.expected, eval.Inspect())
+		}
+	}
+}
+
+func testEval(input string) object.Object{
+	p := parser.New(lexer.New(input))
+	return evaluation.Eval(p.ParseProgram(), object.NewEnv())
+}
@@ -7,10 +7,13 @@ const (
 	IF="if"
 	ELSE="else"
 	RETURN="return"
+	IMPORT="import"
+	EXPORT="export"
 
 	//literals
 	VARIABLE="VAR"
 	STRING="STR"
+	INTERPOLATED="ISTR"
 	NUMBER="INT"
 	TRUE="T"
 	FALSE="F"
@@ -39,6 +42,14 @@ const (
 	MINUS="-"
 	DIVIDE="/"
 	MULTIPLY="*"
+	ELLIPSIS="..."
+	DOT="."
+	OPTIONALDOT="?."
+	OPTIONALINDEX="?["
+	COALESCE="??"
+	QUESTION="?"
+	ARROW="=>"
+	PIPE="|>"
 
 	//illegal
 	INV="INVALID"
@@ -55,6 +66,8 @@ var KeywordMap = map[string]TokenType{
 	"if":IF,
 	"else":ELSE,
 	"return":RETURN,
+	"import":IMPORT,
+	"export":EXPORT,
 }
 
 type TokenType string